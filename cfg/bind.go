@@ -0,0 +1,235 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Invalid describes a key that could not be bound to a struct field or failed
+// validation against a Schema.
+type Invalid struct {
+	Key string `json:"key"`
+	Err string `json:"error"`
+}
+
+// InvalidError is a custom error type listing every key that could not be
+// bound or validated.
+type InvalidError []Invalid
+
+// Error implements the error interface for InvalidError.
+func (err InvalidError) Error() string {
+	strs := make([]string, len(err))
+	for i, v := range err {
+		strs[i] = fmt.Sprintf("{%s:%s}", v.Key, v.Err)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// Set of reflect types that need special handling when binding.
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	timeType          = reflect.TypeOf(time.Time{})
+	urlType           = reflect.TypeOf(&url.URL{})
+	stringSliceType   = reflect.TypeOf([]string{})
+	intSliceType      = reflect.TypeOf([]int{})
+	durationSliceType = reflect.TypeOf([]time.Duration{})
+	stringMapType     = reflect.TypeOf(map[string]string{})
+)
+
+// Bind populates the struct pointed to by dst with values from the Config.
+// Fields are matched to keys using the cfg struct tag. A default tag provides
+// a value when the key is not found and a required tag reports an error when
+// the key is not found and there is no default:
+//
+//	type DB struct {
+//		Host    string        `cfg:"HOST" default:"localhost"`
+//		Port    int           `cfg:"PORT" required:"true"`
+//		Timeout time.Duration `cfg:"TIMEOUT" default:"5s"`
+//	}
+//
+//	type App struct {
+//		DB DB `cfg:"DB_"`
+//	}
+//
+// The cfg tag of a nested struct is used as a prefix for the keys of its
+// fields, so the Host field above is read from DB_HOST. Fields without a cfg
+// tag, or with a tag of "-", are left untouched. Bind does not stop at the
+// first problem, every key that is missing or can't be converted is reported
+// in the returned InvalidError.
+func (c *Config) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("destination must be a non-nil pointer to a struct")
+	}
+
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var inv InvalidError
+	r.bindStruct(v.Elem(), prefix, &inv)
+
+	if inv != nil {
+		return inv
+	}
+
+	return nil
+}
+
+// bindStruct walks the fields of the struct value, binding each tagged field
+// and recursing into nested structs. Problems are appended to inv.
+func (c *Config) bindStruct(v reflect.Value, prefix string, inv *InvalidError) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		// Unexported fields can't be set.
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tag, tagged := sf.Tag.Lookup("cfg")
+		if tag == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		// Nested structs have their fields bound with the extended prefix.
+		if sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			c.bindStruct(fv, prefix+tag, inv)
+			continue
+		}
+
+		if !tagged {
+			continue
+		}
+
+		key := prefix + tag
+
+		value, found := c.m[key]
+		if !found {
+			def, ok := sf.Tag.Lookup("default")
+			switch {
+			case ok:
+				value = def
+			case sf.Tag.Get("required") == "true":
+				*inv = append(*inv, Invalid{Key: key, Err: "required key not found"})
+				continue
+			default:
+				continue
+			}
+		}
+
+		if err := setField(fv, value); err != nil {
+			*inv = append(*inv, Invalid{Key: key, Err: err.Error()})
+		}
+	}
+}
+
+// setField converts the value to the type of the field and sets it.
+func setField(fv reflect.Value, value string) error {
+	switch fv.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+
+	case timeType:
+		tv, err := parseTime(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(tv))
+		return nil
+
+	case urlType:
+		u, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(u))
+		return nil
+
+	case stringSliceType:
+		sv, err := parseStringSlice(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(sv))
+		return nil
+
+	case intSliceType:
+		iv, err := parseIntSlice(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(iv))
+		return nil
+
+	case durationSliceType:
+		dv, err := parseDurationSlice(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(dv))
+		return nil
+
+	case stringMapType:
+		mv, err := parseStringMap(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(mv))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+
+	case reflect.Bool:
+		bv, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(bv)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		iv, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(iv)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uv, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uv)
+
+	case reflect.Float32, reflect.Float64:
+		fl, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(fl)
+
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
@@ -2,8 +2,11 @@ package cfg
 
 import (
 	"bytes"
+	"encoding/csv"
 	"fmt"
+	"math"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,54 +14,205 @@ import (
 )
 
 // Config is a goroutine safe configuration store, with a map of values
-// set from a config Provider.
+// set from a config Provider. Values can reference other keys using ${KEY},
+// which is resolved when the Config is created and whenever a value is set.
 type Config struct {
-	m  map[string]string
-	mu sync.RWMutex
+	m      map[string]string
+	raw    map[string]string
+	read   map[string]string
+	env    bool
+	dec    Decrypter
+	src    map[string]string
+	secret map[string]bool
+	subs   []subscription
+	redact *Redactor
+	mu     sync.RWMutex
+
+	// A view created with Sub holds no values of its own, it reads and writes
+	// the keys of parent that start with prefix.
+	parent *Config
+	prefix string
 }
 
+// SourceSet is the source recorded for keys added or modified with one of the
+// Set functions.
+const SourceSet = "set"
+
 // Provider is implemented by the user to provide the configuration as a map.
-// There are currently two Providers implemented, EnvProvider and MapProvider.
+// There are Providers implemented for the environment, dotenv, JSON and TOML
+// files, maps and for layering other Providers.
 type Provider interface {
 	Provide() (map[string]string, error)
 }
 
 // New populates a new Config from a Provider. It will return an error if there
-// was any problem reading from the Provider.
+// was any problem reading from the Provider or the values contain a reference
+// cycle.
 func New(p Provider) (*Config, error) {
-	m, err := p.Provide()
+	pv, err := provide(p)
 	if err != nil {
 		return nil, err
 	}
 
-	c := &Config{m: m}
+	m, err := resolve(pv.m, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{m: m, raw: pv.m, read: pv.m, src: pv.src, secret: pv.secret}
 
 	return c, nil
 }
 
-// Log returns a string to help with logging your configuration. It excludes
-// any values whose key contains the string "PASS".
+// Log returns a string to help with logging your configuration. Keys are
+// sorted and secret values are masked according to the Config's Redactor,
+// see SetRedactor, along with any values that were encrypted. Each line
+// includes the source of the key when it is known.
 func (c *Config) Log() string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	red := r.redactor()
 
 	var buf bytes.Buffer
-	for k, v := range c.m {
-		if !strings.Contains(k, "PASS") {
-			buf.WriteString(k + "=" + v + "\n")
+	for _, k := range r.sortedKeys(prefix) {
+		buf.WriteString(strings.TrimPrefix(k, prefix) + "=" + r.redactValue(red, k))
+		if s := r.src[k]; s != "" {
+			buf.WriteString(" (" + s + ")")
 		}
+		buf.WriteString("\n")
 	}
 
 	return buf.String()
 }
 
+// sortedKeys returns the keys of the Config that start with prefix in sorted
+// order. The caller must hold the lock.
+func (c *Config) sortedKeys(prefix string) []string {
+	keys := make([]string, 0, len(c.m))
+	for k := range c.m {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// view returns the Config holding the values and the prefix of the keys that
+// are visible through c.
+func (c *Config) view() (*Config, string) {
+	if c.parent != nil {
+		return c.parent, c.prefix
+	}
+
+	return c, ""
+}
+
+// value returns the value of the given key and whether it was found.
+func (c *Config) value(key string) (string, bool) {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, found := r.m[prefix+key]
+	return value, found
+}
+
+// Keys returns the keys of the Config in sorted order.
+func (c *Config) Keys() []string {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := r.sortedKeys(prefix)
+	for i, k := range keys {
+		keys[i] = strings.TrimPrefix(k, prefix)
+	}
+
+	return keys
+}
+
+// Has reports whether the given key was found.
+func (c *Config) Has(key string) bool {
+	_, found := c.value(key)
+	return found
+}
+
+// Source returns the name of the Provider that supplied the value of the given
+// key, or SourceSet if it was last set with one of the Set functions. It will
+// return an error if the key was not found.
+func (c *Config) Source(key string) (string, error) {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, found := r.m[prefix+key]; !found {
+		return "", fmt.Errorf("unknown key %s", key)
+	}
+
+	return r.src[prefix+key], nil
+}
+
+// set adds or modifies the value for the specified key, see setValues.
+func (c *Config) set(key string, value string) {
+	c.setValues(map[string]string{key: value})
+}
+
+// setValues adds or modifies the values for the specified keys, records that
+// they were set by the application and notifies any subscribers of the changes
+// at once. All references are resolved again so keys that reference these keys
+// are updated. The Set functions can't report a reference cycle, the keys that
+// are part of one keep their unresolved value.
+func (c *Config) setValues(values map[string]string) {
+	if c.parent != nil {
+		pv := make(map[string]string, len(values))
+		for k, v := range values {
+			pv[c.prefix+k] = v
+		}
+
+		c.parent.setValues(pv)
+		return
+	}
+
+	c.mu.Lock()
+	if c.src == nil {
+		c.src = make(map[string]string)
+	}
+
+	// The raw values are copied so the values last read from the Provider
+	// are kept as they were read.
+	raw := make(map[string]string, len(c.raw)+len(values))
+	for k, v := range c.raw {
+		raw[k] = v
+	}
+	c.raw = raw
+
+	for k, v := range values {
+		c.raw[k] = v
+		c.src[k] = SourceSet
+		delete(c.secret, k)
+	}
+
+	old := c.m
+	m, _ := resolve(c.raw, c.env, c.dec)
+	c.m = m
+	subs := c.subs
+	c.mu.Unlock()
+
+	notify(subs, old, m)
+}
+
 // String returns the value of the given key as a string. It will return an
 // error if key was not found.
 func (c *Config) String(key string) (string, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return "", fmt.Errorf("unknown key %s", key)
 	}
@@ -69,10 +223,7 @@ func (c *Config) String(key string) (string, error) {
 // MustString returns the value of the given key as a string. It will panic if
 // the key was not found.
 func (c *Config) MustString(key string) string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Sprintf("Unknown key %s !", key))
 	}
@@ -83,20 +234,13 @@ func (c *Config) MustString(key string) string {
 // SetString adds or modifies the configuration for the specified key and
 // value.
 func (c *Config) SetString(key string, value string) {
-	c.mu.Lock()
-	{
-		c.m[key] = value
-	}
-	c.mu.Unlock()
+	c.set(key, value)
 }
 
 // Int returns the value of the given key as an int. It will return an error if
 // the key was not found or the value can't be converted to an int.
 func (c *Config) Int(key string) (int, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return 0, fmt.Errorf("unknown key %s", key)
 	}
@@ -112,10 +256,7 @@ func (c *Config) Int(key string) (int, error) {
 // MustInt returns the value of the given key as an int. It will panic if the
 // key was not found or the value can't be converted to an int.
 func (c *Config) MustInt(key string) int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Sprintf("Unknown key %s !", key))
 	}
@@ -130,25 +271,18 @@ func (c *Config) MustInt(key string) int {
 
 // SetInt adds or modifies the configuration for the specified key and value.
 func (c *Config) SetInt(key string, value int) {
-	c.mu.Lock()
-	{
-		c.m[key] = strconv.Itoa(value)
-	}
-	c.mu.Unlock()
+	c.set(key, strconv.Itoa(value))
 }
 
 // Time returns the value of the given key as a Time. It will return an error
 // if the key was not found or the value can't be converted to a Time.
 func (c *Config) Time(key string) (time.Time, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return time.Time{}, fmt.Errorf("unknown key %s", key)
 	}
 
-	tv, err := time.Parse(time.UnixDate, value)
+	tv, err := parseTime(value)
 	if err != nil {
 		return tv, err
 	}
@@ -159,15 +293,12 @@ func (c *Config) Time(key string) (time.Time, error) {
 // MustTime returns the value of the given key as a Time. It will panic if the
 // key was not found or the value can't be converted to a Time.
 func (c *Config) MustTime(key string) time.Time {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Sprintf("unknown key %s", key))
 	}
 
-	tv, err := time.Parse(time.UnixDate, value)
+	tv, err := parseTime(value)
 	if err != nil {
 		panic(fmt.Sprintf("key %q value is not a Time", key))
 	}
@@ -177,31 +308,18 @@ func (c *Config) MustTime(key string) time.Time {
 
 // SetTime adds or modifies the configuration for the specified key and value.
 func (c *Config) SetTime(key string, value time.Time) {
-	c.mu.Lock()
-	{
-		c.m[key] = value.Format(time.UnixDate)
-	}
-	c.mu.Unlock()
+	c.set(key, value.Format(time.UnixDate))
 }
 
 // Bool returns the bool value of a given key as a bool. It will return an
 // error if the key was not found or the value can't be converted to a bool.
 func (c *Config) Bool(key string) (bool, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return false, fmt.Errorf("unknown key %s", key)
 	}
 
-	if value == "on" || value == "yes" {
-		value = "true"
-	} else if value == "off" || value == "no" {
-		value = "false"
-	}
-
-	val, err := strconv.ParseBool(value)
+	val, err := parseBool(value)
 	if err != nil {
 		return false, err
 	}
@@ -212,21 +330,12 @@ func (c *Config) Bool(key string) (bool, error) {
 // MustBool returns the bool value of a given key as a bool. It will panic if
 // the key was not found or the value can't be converted to a bool.
 func (c *Config) MustBool(key string) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Sprintf("unknown key %s", key))
 	}
 
-	if value == "on" || value == "yes" {
-		value = "true"
-	} else if value == "off" || value == "no" {
-		value = "false"
-	}
-
-	val, err := strconv.ParseBool(value)
+	val, err := parseBool(value)
 	if err != nil {
 		return false
 	}
@@ -241,20 +350,13 @@ func (c *Config) SetBool(key string, value bool) {
 		str = "true"
 	}
 
-	c.mu.Lock()
-	{
-		c.m[key] = str
-	}
-	c.mu.Unlock()
+	c.set(key, str)
 }
 
 // URL returns the value of the given key as a URL. It will return an error if
 // the key was not found or the value can't be converted to a URL.
 func (c *Config) URL(key string) (*url.URL, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return nil, fmt.Errorf("unknown key %s", key)
 	}
@@ -270,10 +372,7 @@ func (c *Config) URL(key string) (*url.URL, error) {
 // MustURL returns the value of the given key as a URL. It will panic if the
 // key was not found or the value can't be converted to a URL.
 func (c *Config) MustURL(key string) *url.URL {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Sprintf("unknown key %s", key))
 	}
@@ -288,20 +387,13 @@ func (c *Config) MustURL(key string) *url.URL {
 
 // SetURL adds or modifies the configuration for the specified key and value.
 func (c *Config) SetURL(key string, value *url.URL) {
-	c.mu.Lock()
-	{
-		c.m[key] = value.String()
-	}
-	c.mu.Unlock()
+	c.set(key, value.String())
 }
 
 // Duration returns the value of the given key as a Duration. It will return an
 // error if the key was not found or the value can't be converted to a Duration.
 func (c *Config) Duration(key string) (time.Duration, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		return time.Duration(0), fmt.Errorf("unknown key %s", key)
 	}
@@ -317,10 +409,7 @@ func (c *Config) Duration(key string) (time.Duration, error) {
 // MustDuration returns the value of the given key as a Duration. It will panic
 // if the key was not found or the value can't be converted into a Duration.
 func (c *Config) MustDuration(key string) time.Duration {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	value, found := c.m[key]
+	value, found := c.value(key)
 	if !found {
 		panic(fmt.Errorf("unknown key %s", key))
 	}
@@ -336,9 +425,699 @@ func (c *Config) MustDuration(key string) time.Duration {
 // SetDuration adds or modifies the configuration for a given duration at a
 // specific key.
 func (c *Config) SetDuration(key string, value time.Duration) {
-	c.mu.Lock()
-	{
-		c.m[key] = value.String()
+	c.set(key, value.String())
+}
+
+// Float64 returns the value of the given key as a float64. It will return an
+// error if the key was not found or the value can't be converted to a float64.
+func (c *Config) Float64(key string) (float64, error) {
+	value, found := c.value(key)
+	if !found {
+		return 0, fmt.Errorf("unknown key %s", key)
 	}
-	c.mu.Unlock()
+
+	fv, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return fv, nil
+}
+
+// MustFloat64 returns the value of the given key as a float64. It will panic if
+// the key was not found or the value can't be converted to a float64.
+func (c *Config) MustFloat64(key string) float64 {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	fv, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a float64", key))
+	}
+
+	return fv
+}
+
+// SetFloat64 adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetFloat64(key string, value float64) {
+	c.set(key, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Int64 returns the value of the given key as an int64. It will return an error
+// if the key was not found or the value can't be converted to an int64.
+func (c *Config) Int64(key string) (int64, error) {
+	value, found := c.value(key)
+	if !found {
+		return 0, fmt.Errorf("unknown key %s", key)
+	}
+
+	iv, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return iv, nil
+}
+
+// MustInt64 returns the value of the given key as an int64. It will panic if
+// the key was not found or the value can't be converted to an int64.
+func (c *Config) MustInt64(key string) int64 {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	iv, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not an int64", key))
+	}
+
+	return iv
+}
+
+// SetInt64 adds or modifies the configuration for the specified key and value.
+func (c *Config) SetInt64(key string, value int64) {
+	c.set(key, strconv.FormatInt(value, 10))
+}
+
+// Uint returns the value of the given key as a uint. It will return an error if
+// the key was not found or the value can't be converted to a uint.
+func (c *Config) Uint(key string) (uint, error) {
+	value, found := c.value(key)
+	if !found {
+		return 0, fmt.Errorf("unknown key %s", key)
+	}
+
+	uv, err := parseUint(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return uv, nil
+}
+
+// MustUint returns the value of the given key as a uint. It will panic if the
+// key was not found or the value can't be converted to a uint.
+func (c *Config) MustUint(key string) uint {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	uv, err := parseUint(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a uint", key))
+	}
+
+	return uv
+}
+
+// SetUint adds or modifies the configuration for the specified key and value.
+func (c *Config) SetUint(key string, value uint) {
+	c.set(key, strconv.FormatUint(uint64(value), 10))
+}
+
+// ByteSize returns the value of the given key as a byte size. It will return an
+// error if the key was not found or the value can't be converted to a byte
+// size. The value may use a decimal (KB, MB, GB, TB, PB) or binary (KiB, MiB,
+// GiB, TiB, PiB) unit suffix such as "64MiB".
+func (c *Config) ByteSize(key string) (uint64, error) {
+	value, found := c.value(key)
+	if !found {
+		return 0, fmt.Errorf("unknown key %s", key)
+	}
+
+	bs, err := parseByteSize(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return bs, nil
+}
+
+// MustByteSize returns the value of the given key as a byte size. It will panic
+// if the key was not found or the value can't be converted to a byte size.
+func (c *Config) MustByteSize(key string) uint64 {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	bs, err := parseByteSize(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a byte size", key))
+	}
+
+	return bs
+}
+
+// SetByteSize adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetByteSize(key string, value uint64) {
+	c.set(key, formatByteSize(value))
+}
+
+// StringSlice returns the value of the given key as a slice of strings. It will
+// return an error if the key was not found or the value can't be converted to a
+// slice of strings. The value is a comma separated list where elements
+// containing commas or quotes are enclosed in double quotes.
+func (c *Config) StringSlice(key string) ([]string, error) {
+	value, found := c.value(key)
+	if !found {
+		return nil, fmt.Errorf("unknown key %s", key)
+	}
+
+	sv, err := parseStringSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return sv, nil
+}
+
+// MustStringSlice returns the value of the given key as a slice of strings. It
+// will panic if the key was not found or the value can't be converted to a
+// slice of strings.
+func (c *Config) MustStringSlice(key string) []string {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	sv, err := parseStringSlice(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a slice of strings", key))
+	}
+
+	return sv
+}
+
+// SetStringSlice adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetStringSlice(key string, value []string) {
+	c.set(key, formatStringSlice(value))
+}
+
+// IntSlice returns the value of the given key as a slice of ints. It will
+// return an error if the key was not found or the value can't be converted to a
+// slice of ints. The value is a comma separated list of ints.
+func (c *Config) IntSlice(key string) ([]int, error) {
+	value, found := c.value(key)
+	if !found {
+		return nil, fmt.Errorf("unknown key %s", key)
+	}
+
+	iv, err := parseIntSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return iv, nil
+}
+
+// MustIntSlice returns the value of the given key as a slice of ints. It will
+// panic if the key was not found or the value can't be converted to a slice of
+// ints.
+func (c *Config) MustIntSlice(key string) []int {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	iv, err := parseIntSlice(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a slice of ints", key))
+	}
+
+	return iv
+}
+
+// SetIntSlice adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetIntSlice(key string, value []int) {
+	c.set(key, formatIntSlice(value))
+}
+
+// DurationSlice returns the value of the given key as a slice of Durations. It
+// will return an error if the key was not found or the value can't be converted
+// to a slice of Durations. The value is a comma separated list of Durations.
+func (c *Config) DurationSlice(key string) ([]time.Duration, error) {
+	value, found := c.value(key)
+	if !found {
+		return nil, fmt.Errorf("unknown key %s", key)
+	}
+
+	dv, err := parseDurationSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return dv, nil
+}
+
+// MustDurationSlice returns the value of the given key as a slice of Durations.
+// It will panic if the key was not found or the value can't be converted to a
+// slice of Durations.
+func (c *Config) MustDurationSlice(key string) []time.Duration {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	dv, err := parseDurationSlice(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a slice of Durations", key))
+	}
+
+	return dv
+}
+
+// SetDurationSlice adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetDurationSlice(key string, value []time.Duration) {
+	c.set(key, formatDurationSlice(value))
+}
+
+// StringMap returns the value of the given key as a map of strings. It will
+// return an error if the key was not found or the value can't be converted to a
+// map of strings. The value is a comma separated list of key:value pairs such
+// as "k1:v1,k2:v2".
+func (c *Config) StringMap(key string) (map[string]string, error) {
+	value, found := c.value(key)
+	if !found {
+		return nil, fmt.Errorf("unknown key %s", key)
+	}
+
+	mv, err := parseStringMap(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return mv, nil
+}
+
+// MustStringMap returns the value of the given key as a map of strings. It will
+// panic if the key was not found or the value can't be converted to a map of
+// strings.
+func (c *Config) MustStringMap(key string) map[string]string {
+	value, found := c.value(key)
+	if !found {
+		panic(fmt.Sprintf("unknown key %s", key))
+	}
+
+	mv, err := parseStringMap(value)
+	if err != nil {
+		panic(fmt.Sprintf("key %q value is not a map of strings", key))
+	}
+
+	return mv
+}
+
+// SetStringMap adds or modifies the configuration for the specified key and
+// value.
+func (c *Config) SetStringMap(key string, value map[string]string) {
+	c.set(key, formatStringMap(value))
+}
+
+// StringOr returns the value of the given key as a string. It will return def
+// if the key was not found or the value can't be converted to a string.
+func (c *Config) StringOr(key string, def string) string {
+	v, err := c.String(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// IntOr returns the value of the given key as an int. It will return def if the
+// key was not found or the value can't be converted to an int.
+func (c *Config) IntOr(key string, def int) int {
+	v, err := c.Int(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Int64Or returns the value of the given key as an int64. It will return def if
+// the key was not found or the value can't be converted to an int64.
+func (c *Config) Int64Or(key string, def int64) int64 {
+	v, err := c.Int64(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// UintOr returns the value of the given key as a uint. It will return def if
+// the key was not found or the value can't be converted to a uint.
+func (c *Config) UintOr(key string, def uint) uint {
+	v, err := c.Uint(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Float64Or returns the value of the given key as a float64. It will return def
+// if the key was not found or the value can't be converted to a float64.
+func (c *Config) Float64Or(key string, def float64) float64 {
+	v, err := c.Float64(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// BoolOr returns the value of the given key as a bool. It will return def if
+// the key was not found or the value can't be converted to a bool.
+func (c *Config) BoolOr(key string, def bool) bool {
+	v, err := c.Bool(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// TimeOr returns the value of the given key as a Time. It will return def if
+// the key was not found or the value can't be converted to a Time.
+func (c *Config) TimeOr(key string, def time.Time) time.Time {
+	v, err := c.Time(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// URLOr returns the value of the given key as a URL. It will return def if the
+// key was not found or the value can't be converted to a URL.
+func (c *Config) URLOr(key string, def *url.URL) *url.URL {
+	v, err := c.URL(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// DurationOr returns the value of the given key as a Duration. It will return
+// def if the key was not found or the value can't be converted to a Duration.
+func (c *Config) DurationOr(key string, def time.Duration) time.Duration {
+	v, err := c.Duration(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// ByteSizeOr returns the value of the given key as a byte size. It will return
+// def if the key was not found or the value can't be converted to a byte size.
+func (c *Config) ByteSizeOr(key string, def uint64) uint64 {
+	v, err := c.ByteSize(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// StringSliceOr returns the value of the given key as a slice of strings. It
+// will return def if the key was not found or the value can't be converted to a
+// slice of strings.
+func (c *Config) StringSliceOr(key string, def []string) []string {
+	v, err := c.StringSlice(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// IntSliceOr returns the value of the given key as a slice of ints. It will
+// return def if the key was not found or the value can't be converted to a
+// slice of ints.
+func (c *Config) IntSliceOr(key string, def []int) []int {
+	v, err := c.IntSlice(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// DurationSliceOr returns the value of the given key as a slice of Durations.
+// It will return def if the key was not found or the value can't be converted
+// to a slice of Durations.
+func (c *Config) DurationSliceOr(key string, def []time.Duration) []time.Duration {
+	v, err := c.DurationSlice(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// StringMapOr returns the value of the given key as a map of strings. It will
+// return def if the key was not found or the value can't be converted to a map
+// of strings.
+func (c *Config) StringMapOr(key string, def map[string]string) map[string]string {
+	v, err := c.StringMap(key)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// parseBool converts the value to a bool. Besides the values accepted by
+// strconv.ParseBool it understands "on", "yes", "off" and "no".
+func parseBool(value string) (bool, error) {
+	switch value {
+	case "on", "yes":
+		value = "true"
+	case "off", "no":
+		value = "false"
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// parseTime converts the value to a Time using the time.UnixDate layout.
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(time.UnixDate, value)
+}
+
+// parseUint converts the value to a uint.
+func parseUint(value string) (uint, error) {
+	uv, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(uv), nil
+}
+
+// byteUnits maps the supported byte size suffixes to their multiplier.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// parseByteSize converts a human readable size such as "64MiB" or "1.5GB" to
+// a number of bytes.
+func parseByteSize(value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+
+	idx := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if idx == -1 {
+		idx = len(value)
+	}
+
+	num, err := strconv.ParseFloat(value[:idx], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+
+	mult, found := byteUnits[strings.ToLower(strings.TrimSpace(value[idx:]))]
+	if !found {
+		return 0, fmt.Errorf("invalid byte size unit %q", value[idx:])
+	}
+
+	size := num * mult
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("byte size %q out of range", value)
+	}
+
+	return uint64(size), nil
+}
+
+// formatByteSize formats a number of bytes using the largest binary unit that
+// represents it exactly.
+func formatByteSize(value uint64) string {
+	units := []struct {
+		suffix string
+		size   uint64
+	}{
+		{"PiB", 1 << 50},
+		{"TiB", 1 << 40},
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+	}
+
+	for _, u := range units {
+		if value != 0 && value%u.size == 0 {
+			return strconv.FormatUint(value/u.size, 10) + u.suffix
+		}
+	}
+
+	return strconv.FormatUint(value, 10) + "B"
+}
+
+// parseStringSlice converts a comma separated list to a slice of strings.
+// Elements containing commas or quotes must be enclosed in double quotes.
+func parseStringSlice(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return []string{}, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(value))
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) != 1 {
+		return nil, fmt.Errorf("invalid list %q", value)
+	}
+
+	return records[0], nil
+}
+
+// formatStringSlice formats the slice as a comma separated list, quoting
+// elements where required.
+func formatStringSlice(value []string) string {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	w.Write(value)
+	w.Flush()
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// parseIntSlice converts a comma separated list to a slice of ints.
+func parseIntSlice(value string) ([]int, error) {
+	strs, err := parseStringSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	ivs := make([]int, len(strs))
+	for i, s := range strs {
+		if ivs[i], err = strconv.Atoi(strings.TrimSpace(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	return ivs, nil
+}
+
+// formatIntSlice formats the slice as a comma separated list.
+func formatIntSlice(value []int) string {
+	strs := make([]string, len(value))
+	for i, v := range value {
+		strs[i] = strconv.Itoa(v)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// parseDurationSlice converts a comma separated list to a slice of Durations.
+func parseDurationSlice(value string) ([]time.Duration, error) {
+	strs, err := parseStringSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dvs := make([]time.Duration, len(strs))
+	for i, s := range strs {
+		if dvs[i], err = time.ParseDuration(strings.TrimSpace(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	return dvs, nil
+}
+
+// formatDurationSlice formats the slice as a comma separated list.
+func formatDurationSlice(value []time.Duration) string {
+	strs := make([]string, len(value))
+	for i, v := range value {
+		strs[i] = v.String()
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// parseStringMap converts a comma separated list of key:value pairs to a map.
+func parseStringMap(value string) (map[string]string, error) {
+	strs, err := parseStringSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	mv := make(map[string]string, len(strs))
+	for _, s := range strs {
+		idx := strings.Index(s, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid key:value pair %q", s)
+		}
+
+		mv[strings.TrimSpace(s[:idx])] = s[idx+1:]
+	}
+
+	return mv, nil
+}
+
+// formatStringMap formats the map as a comma separated list of key:value
+// pairs sorted by key.
+func formatStringMap(value map[string]string) string {
+	keys := make([]string, 0, len(value))
+	for k := range value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	strs := make([]string, len(keys))
+	for i, k := range keys {
+		strs[i] = k + ":" + value[k]
+	}
+
+	return formatStringSlice(strs)
 }
@@ -0,0 +1,115 @@
+package cfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// dbConfig is a nested struct used to test binding with prefixes.
+type dbConfig struct {
+	Host    string        `cfg:"HOST" default:"localhost"`
+	Port    int           `cfg:"PORT" required:"true"`
+	Timeout time.Duration `cfg:"TIMEOUT" default:"5s"`
+}
+
+// appConfig is the struct used to test binding.
+type appConfig struct {
+	Name    string   `cfg:"NAME"`
+	Debug   bool     `cfg:"DEBUG"`
+	Ratio   float64  `cfg:"RATIO" default:"0.5"`
+	DB      dbConfig `cfg:"DB_"`
+	Ignored string   `cfg:"-"`
+}
+
+// TestBind validates the ability to populate a struct from a Config.
+func TestBind(t *testing.T) {
+	t.Log("Given the need to bind configuration to a struct.")
+	{
+		t.Log("\tWhen all required keys are provided.")
+		{
+			c, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{
+					"NAME":    "kit",
+					"DEBUG":   "on",
+					"DB_PORT": "5432",
+					"DB_HOST": "db.local",
+					"IGNORED": "value",
+				},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			var app appConfig
+			if err := c.Bind(&app); err != nil {
+				t.Fatalf("\t\t%s Should bind without error : %v", failed, err)
+			}
+			t.Logf("\t\t%s Should bind without error.", success)
+
+			exp := appConfig{
+				Name:  "kit",
+				Debug: true,
+				Ratio: 0.5,
+				DB: dbConfig{
+					Host:    "db.local",
+					Port:    5432,
+					Timeout: 5 * time.Second,
+				},
+			}
+			if app != exp {
+				t.Logf("\t\tGot : %+v", app)
+				t.Logf("\t\tExp : %+v", exp)
+				t.Errorf("\t\t%s Should populate the struct with values and defaults.", failed)
+			} else {
+				t.Logf("\t\t%s Should populate the struct with values and defaults.", success)
+			}
+		}
+
+		t.Log("\tWhen keys are missing or malformed.")
+		{
+			c, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{
+					"DEBUG":      "maybe",
+					"DB_TIMEOUT": "forever",
+				},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			var app appConfig
+			err = c.Bind(&app)
+
+			inv, ok := err.(cfg.InvalidError)
+			if !ok {
+				t.Fatalf("\t\t%s Should return an InvalidError : %v", failed, err)
+			}
+			t.Logf("\t\t%s Should return an InvalidError.", success)
+
+			keys := map[string]bool{}
+			for _, v := range inv {
+				keys[v.Key] = true
+			}
+
+			for _, key := range []string{"DEBUG", "DB_PORT", "DB_TIMEOUT"} {
+				if !keys[key] {
+					t.Errorf("\t\t%s Should report key %q : %v", failed, key, err)
+				} else {
+					t.Logf("\t\t%s Should report key %q.", success, key)
+				}
+			}
+		}
+
+		t.Log("\tWhen the destination is not a pointer to a struct.")
+		{
+			var app appConfig
+			if err := cfg.Bind(app); err == nil {
+				t.Errorf("\t\t%s Should return an error.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error.", success)
+			}
+		}
+	}
+}
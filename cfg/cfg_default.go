@@ -1,6 +1,8 @@
 package cfg
 
 import (
+	"context"
+	"io"
 	"net/url"
 	"time"
 )
@@ -17,23 +19,44 @@ func Init(p Provider) error {
 	defer c.mu.Unlock()
 
 	// Get the provided configuration.
-	m, err := p.Provide()
+	pv, err := provide(p)
+	if err != nil {
+		return err
+	}
+
+	// Resolve any references between the values.
+	m, err := resolve(pv.m, c.env, c.dec)
 	if err != nil {
 		return err
 	}
 
 	// Set it to the global instance.
 	c.m = m
+	c.raw = pv.m
+	c.read = pv.m
+	c.src = pv.src
+	c.secret = pv.secret
 
 	return nil
 }
 
-// Log returns a string to help with logging the package's default Config. It
-// excludes any values whose key contains the string "PASS".
+// Log returns a string to help with logging the package's default Config. Keys
+// are sorted and secret values are masked according to the Redactor.
 func Log() string {
 	return c.Log()
 }
 
+// SetRedactor replaces the policy used to mask secret values when the default
+// Config is logged or exported.
+func SetRedactor(r Redactor) {
+	c.SetRedactor(r)
+}
+
+// Masked returns a copy of the default Config with every secret value masked.
+func Masked() map[string]string {
+	return c.Masked()
+}
+
 // String calls the default Config and returns the value of the given key as a
 // string. It will return an error if key was not found.
 func String(key string) (string, error) {
@@ -146,3 +169,330 @@ func MustDuration(key string) time.Duration {
 func SetDuration(key string, value time.Duration) {
 	c.SetDuration(key, value)
 }
+
+// Float64 calls the default Config and returns the value of the given key as a
+// float64. It will return an error if the key was not found or the value can't
+// be converted to a float64.
+func Float64(key string) (float64, error) {
+	return c.Float64(key)
+}
+
+// MustFloat64 calls the default Config and returns the value of the given key
+// as a float64. It will panic if the key was not found or the value can't be
+// converted to a float64.
+func MustFloat64(key string) float64 {
+	return c.MustFloat64(key)
+}
+
+// SetFloat64 adds or modifies the default Config for the specified key and
+// value.
+func SetFloat64(key string, value float64) {
+	c.SetFloat64(key, value)
+}
+
+// Int64 calls the default Config and returns the value of the given key as an
+// int64. It will return an error if the key was not found or the value can't be
+// converted to an int64.
+func Int64(key string) (int64, error) {
+	return c.Int64(key)
+}
+
+// MustInt64 calls the default Config and returns the value of the given key as
+// an int64. It will panic if the key was not found or the value can't be
+// converted to an int64.
+func MustInt64(key string) int64 {
+	return c.MustInt64(key)
+}
+
+// SetInt64 adds or modifies the default Config for the specified key and value.
+func SetInt64(key string, value int64) {
+	c.SetInt64(key, value)
+}
+
+// Uint calls the default Config and returns the value of the given key as a
+// uint. It will return an error if the key was not found or the value can't be
+// converted to a uint.
+func Uint(key string) (uint, error) {
+	return c.Uint(key)
+}
+
+// MustUint calls the default Config and returns the value of the given key as a
+// uint. It will panic if the key was not found or the value can't be converted
+// to a uint.
+func MustUint(key string) uint {
+	return c.MustUint(key)
+}
+
+// SetUint adds or modifies the default Config for the specified key and value.
+func SetUint(key string, value uint) {
+	c.SetUint(key, value)
+}
+
+// ByteSize calls the default Config and returns the value of the given key as a
+// byte size. It will return an error if the key was not found or the value
+// can't be converted to a byte size.
+func ByteSize(key string) (uint64, error) {
+	return c.ByteSize(key)
+}
+
+// MustByteSize calls the default Config and returns the value of the given key
+// as a byte size. It will panic if the key was not found or the value can't be
+// converted to a byte size.
+func MustByteSize(key string) uint64 {
+	return c.MustByteSize(key)
+}
+
+// SetByteSize adds or modifies the default Config for the specified key and
+// value.
+func SetByteSize(key string, value uint64) {
+	c.SetByteSize(key, value)
+}
+
+// StringSlice calls the default Config and returns the value of the given key
+// as a slice of strings. It will return an error if the key was not found or
+// the value can't be converted to a slice of strings.
+func StringSlice(key string) ([]string, error) {
+	return c.StringSlice(key)
+}
+
+// MustStringSlice calls the default Config and returns the value of the given
+// key as a slice of strings. It will panic if the key was not found or the
+// value can't be converted to a slice of strings.
+func MustStringSlice(key string) []string {
+	return c.MustStringSlice(key)
+}
+
+// SetStringSlice adds or modifies the default Config for the specified key and
+// value.
+func SetStringSlice(key string, value []string) {
+	c.SetStringSlice(key, value)
+}
+
+// IntSlice calls the default Config and returns the value of the given key as a
+// slice of ints. It will return an error if the key was not found or the value
+// can't be converted to a slice of ints.
+func IntSlice(key string) ([]int, error) {
+	return c.IntSlice(key)
+}
+
+// MustIntSlice calls the default Config and returns the value of the given key
+// as a slice of ints. It will panic if the key was not found or the value can't
+// be converted to a slice of ints.
+func MustIntSlice(key string) []int {
+	return c.MustIntSlice(key)
+}
+
+// SetIntSlice adds or modifies the default Config for the specified key and
+// value.
+func SetIntSlice(key string, value []int) {
+	c.SetIntSlice(key, value)
+}
+
+// DurationSlice calls the default Config and returns the value of the given key
+// as a slice of Durations. It will return an error if the key was not found or
+// the value can't be converted to a slice of Durations.
+func DurationSlice(key string) ([]time.Duration, error) {
+	return c.DurationSlice(key)
+}
+
+// MustDurationSlice calls the default Config and returns the value of the given
+// key as a slice of Durations. It will panic if the key was not found or the
+// value can't be converted to a slice of Durations.
+func MustDurationSlice(key string) []time.Duration {
+	return c.MustDurationSlice(key)
+}
+
+// SetDurationSlice adds or modifies the default Config for the specified key
+// and value.
+func SetDurationSlice(key string, value []time.Duration) {
+	c.SetDurationSlice(key, value)
+}
+
+// StringMap calls the default Config and returns the value of the given key as
+// a map of strings. It will return an error if the key was not found or the
+// value can't be converted to a map of strings.
+func StringMap(key string) (map[string]string, error) {
+	return c.StringMap(key)
+}
+
+// MustStringMap calls the default Config and returns the value of the given key
+// as a map of strings. It will panic if the key was not found or the value
+// can't be converted to a map of strings.
+func MustStringMap(key string) map[string]string {
+	return c.MustStringMap(key)
+}
+
+// SetStringMap adds or modifies the default Config for the specified key and
+// value.
+func SetStringMap(key string, value map[string]string) {
+	c.SetStringMap(key, value)
+}
+
+// StringOr calls the default Config and returns the value of the given key as a
+// string. It will return def if the key was not found or the value can't be
+// converted to a string.
+func StringOr(key string, def string) string {
+	return c.StringOr(key, def)
+}
+
+// IntOr calls the default Config and returns the value of the given key as an
+// int. It will return def if the key was not found or the value can't be
+// converted to an int.
+func IntOr(key string, def int) int {
+	return c.IntOr(key, def)
+}
+
+// Int64Or calls the default Config and returns the value of the given key as an
+// int64. It will return def if the key was not found or the value can't be
+// converted to an int64.
+func Int64Or(key string, def int64) int64 {
+	return c.Int64Or(key, def)
+}
+
+// UintOr calls the default Config and returns the value of the given key as a
+// uint. It will return def if the key was not found or the value can't be
+// converted to a uint.
+func UintOr(key string, def uint) uint {
+	return c.UintOr(key, def)
+}
+
+// Float64Or calls the default Config and returns the value of the given key as
+// a float64. It will return def if the key was not found or the value can't be
+// converted to a float64.
+func Float64Or(key string, def float64) float64 {
+	return c.Float64Or(key, def)
+}
+
+// BoolOr calls the default Config and returns the value of the given key as a
+// bool. It will return def if the key was not found or the value can't be
+// converted to a bool.
+func BoolOr(key string, def bool) bool {
+	return c.BoolOr(key, def)
+}
+
+// TimeOr calls the default Config and returns the value of the given key as a
+// Time. It will return def if the key was not found or the value can't be
+// converted to a Time.
+func TimeOr(key string, def time.Time) time.Time {
+	return c.TimeOr(key, def)
+}
+
+// URLOr calls the default Config and returns the value of the given key as a
+// URL. It will return def if the key was not found or the value can't be
+// converted to a URL.
+func URLOr(key string, def *url.URL) *url.URL {
+	return c.URLOr(key, def)
+}
+
+// DurationOr calls the default Config and returns the value of the given key as
+// a Duration. It will return def if the key was not found or the value can't be
+// converted to a Duration.
+func DurationOr(key string, def time.Duration) time.Duration {
+	return c.DurationOr(key, def)
+}
+
+// ByteSizeOr calls the default Config and returns the value of the given key as
+// a byte size. It will return def if the key was not found or the value can't
+// be converted to a byte size.
+func ByteSizeOr(key string, def uint64) uint64 {
+	return c.ByteSizeOr(key, def)
+}
+
+// StringSliceOr calls the default Config and returns the value of the given key
+// as a slice of strings. It will return def if the key was not found or the
+// value can't be converted to a slice of strings.
+func StringSliceOr(key string, def []string) []string {
+	return c.StringSliceOr(key, def)
+}
+
+// IntSliceOr calls the default Config and returns the value of the given key as
+// a slice of ints. It will return def if the key was not found or the value
+// can't be converted to a slice of ints.
+func IntSliceOr(key string, def []int) []int {
+	return c.IntSliceOr(key, def)
+}
+
+// DurationSliceOr calls the default Config and returns the value of the given
+// key as a slice of Durations. It will return def if the key was not found or
+// the value can't be converted to a slice of Durations.
+func DurationSliceOr(key string, def []time.Duration) []time.Duration {
+	return c.DurationSliceOr(key, def)
+}
+
+// StringMapOr calls the default Config and returns the value of the given key
+// as a map of strings. It will return def if the key was not found or the value
+// can't be converted to a map of strings.
+func StringMapOr(key string, def map[string]string) map[string]string {
+	return c.StringMapOr(key, def)
+}
+
+// Validate checks the default Config against the Schema. Every violation is
+// reported in the returned InvalidError.
+func Validate(s Schema) error {
+	return c.Validate(s)
+}
+
+// Source returns the name of the Provider that supplied the value of the given
+// key in the default Config. It will return an error if the key was not found.
+func Source(key string) (string, error) {
+	return c.Source(key)
+}
+
+// OnChange registers fn to be called whenever any of the given keys in the
+// default Config is added, modified or removed.
+func OnChange(keys []string, fn func(old, new map[string]string)) {
+	c.OnChange(keys, fn)
+}
+
+// Reload reads the configuration from the Provider again and atomically
+// replaces the values in the default Config.
+func Reload(p Provider) error {
+	return c.Reload(p)
+}
+
+// Watch polls the Provider at the given interval and reloads the default
+// Config when the configuration changes. It blocks until the context is done.
+func Watch(ctx context.Context, p Provider, interval time.Duration) error {
+	return c.Watch(ctx, p, interval)
+}
+
+// SetEnvFallback controls whether ${KEY} references in the default Config to
+// keys that are not found are looked up in the process environment.
+func SetEnvFallback(enabled bool) error {
+	return c.SetEnvFallback(enabled)
+}
+
+// SetDecrypter sets the Decrypter used to decrypt values with the
+// EncryptedPrefix in the default Config.
+func SetDecrypter(d Decrypter) error {
+	return c.SetDecrypter(d)
+}
+
+// Bind populates the struct pointed to by dst with values from the default
+// Config. It will return an InvalidError listing every key that was missing or
+// could not be converted.
+func Bind(dst interface{}) error {
+	return c.Bind(dst)
+}
+
+// Export writes the default Config to w in the given format with the keys
+// sorted and secret values masked.
+func Export(w io.Writer, f Format) error {
+	return c.Export(w, f)
+}
+
+// Sub returns a view of the keys of the default Config that start with prefix,
+// with the prefix removed.
+func Sub(prefix string) *Config {
+	return c.Sub(prefix)
+}
+
+// Keys returns the keys of the default Config in sorted order.
+func Keys() []string {
+	return c.Keys()
+}
+
+// Has reports whether the given key was found in the default Config.
+func Has(key string) bool {
+	return c.Has(key)
+}
@@ -0,0 +1,164 @@
+package cfg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestExport validates the ability to export the configuration.
+func TestExport(t *testing.T) {
+	t.Log("Given the need to export configuration.")
+	{
+		values := map[string]string{
+			"NAME":    "kit",
+			"GREET":   "Hello \"World\"\n$HOME ${NAME}",
+			"LITERAL": "$${NAME}",
+			"DB_PASS": "hunter2",
+		}
+
+		c, err := cfg.New(cfg.MapProvider{Map: values})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+		c.SetRedactor(cfg.Redactor{Keys: []string{"DB_PASS"}})
+
+		t.Log("\tWhen exporting as dotenv.")
+		{
+			var buf bytes.Buffer
+			if err := c.Export(&buf, cfg.FormatDotenv); err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := "DB_PASS=\"[REDACTED len=7]\"\nGREET=\"Hello \\\"World\\\"\\n\\$HOME kit\"\nLITERAL=\"\\${NAME}\"\nNAME=kit\n"
+			if buf.String() != exp {
+				t.Errorf("\t\t%s Should write sorted and masked values : got %q", failed, buf.String())
+			} else {
+				t.Logf("\t\t%s Should write sorted and masked values.", success)
+			}
+
+			name := writeTemp(t, buf.String())
+			defer os.Remove(name)
+
+			fc, err := cfg.New(cfg.FileProvider{Filename: name})
+			if err != nil {
+				t.Fatalf("\t\t%s Should be able to read the file back : %v", failed, err)
+			}
+
+			for _, k := range []string{"GREET", "LITERAL"} {
+				if v := fc.MustString(k); v != c.MustString(k) {
+					t.Errorf("\t\t%s Should read back the same value for %q : got %q", failed, k, v)
+				} else {
+					t.Logf("\t\t%s Should read back the same value for %q.", success, k)
+				}
+			}
+		}
+
+		t.Log("\tWhen exporting as JSON.")
+		{
+			var buf bytes.Buffer
+			if err := c.Export(&buf, cfg.FormatJSON); err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			var m map[string]string
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil || m["NAME"] != "kit" || m["DB_PASS"] != "[REDACTED len=7]" {
+				t.Errorf("\t\t%s Should write a JSON object : %v %v", failed, m, err)
+			} else {
+				t.Logf("\t\t%s Should write a JSON object.", success)
+			}
+		}
+
+		t.Log("\tWhen exporting as Go source.")
+		{
+			var buf bytes.Buffer
+			if err := c.Export(&buf, cfg.FormatGo); err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := "cfg.MapProvider{\n\tMap: map[string]string{\n\t\t\"DB_PASS\": \"[REDACTED len=7]\",\n\t\t\"GREET\":   \"Hello \\\"World\\\"\\n$HOME kit\",\n\t\t\"LITERAL\": \"$${NAME}\",\n\t\t\"NAME\":    \"kit\",\n\t},\n}\n"
+			if buf.String() != exp {
+				t.Errorf("\t\t%s Should write a MapProvider literal : got\n%s", failed, buf.String())
+			} else {
+				t.Logf("\t\t%s Should write a MapProvider literal.", success)
+			}
+		}
+
+		t.Log("\tWhen exporting an unknown format.")
+		{
+			if err := c.Export(&bytes.Buffer{}, "yaml"); err == nil {
+				t.Errorf("\t\t%s Should return an error.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error.", success)
+			}
+		}
+	}
+}
+
+// TestSnapshotDiff validates the ability to snapshot and compare
+// configurations.
+func TestSnapshotDiff(t *testing.T) {
+	t.Log("Given the need to compare configurations.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{"HOST": "localhost", "PORT": "80", "DEBUG": "true"},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		s := c.Snapshot()
+
+		c.SetInt("PORT", 8080)
+		c.SetString("NAME", "kit")
+
+		t.Log("\tWhen the Config changes after a Snapshot.")
+		{
+			if v, _ := s.Value("PORT"); v != "80" {
+				t.Errorf("\t\t%s Should keep the old value : got %q", failed, v)
+			} else {
+				t.Logf("\t\t%s Should keep the old value.", success)
+			}
+
+			sc, err := cfg.New(s)
+			if err != nil || sc.MustString("HOST") != "localhost" {
+				t.Errorf("\t\t%s Should be usable as a Provider : %v", failed, err)
+			} else {
+				t.Logf("\t\t%s Should be usable as a Provider.", success)
+			}
+		}
+
+		t.Log("\tWhen comparing two Configs.")
+		{
+			other, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{"HOST": "localhost", "PORT": "9090", "NAME": "kit", "EXTRA": "1"},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			d := c.Diff(other)
+
+			exp := cfg.Diff{
+				Added:   map[string]string{"EXTRA": "1"},
+				Removed: map[string]string{"DEBUG": "true"},
+				Changed: map[string]cfg.Change{"PORT": {Old: "8080", New: "9090"}},
+			}
+			if !reflect.DeepEqual(d, exp) {
+				t.Errorf("\t\t%s Should report the added, removed and changed keys : got %+v", failed, d)
+			} else {
+				t.Logf("\t\t%s Should report the added, removed and changed keys.", success)
+			}
+
+			if !c.Diff(c).Empty() {
+				t.Errorf("\t\t%s Should report no differences with itself.", failed)
+			} else {
+				t.Logf("\t\t%s Should report no differences with itself.", success)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cfg_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestFileProvider validates the ability to load a dotenv file.
+func TestFileProvider(t *testing.T) {
+	t.Log("Given the need to load configuration from a dotenv file.")
+	{
+		os.Setenv("CFG_TEST_USER", "bill")
+		defer os.Unsetenv("CFG_TEST_USER")
+
+		name := writeTemp(t, `# A comment.
+A=1
+export HOST=localhost
+EMPTY=
+SPACED = some value # inline comment
+HASH=a#b
+DQ="Hello\tWorld\n\"quoted\" \$HOME"
+SQ='No ${HOST} \n'
+MULTI="line 1
+line 2"
+URL=http://${HOST}:${PORT:-8080}/$A
+USER=${CFG_TEST_USER}
+UNKNOWN=${CFG_TEST_UNKNOWN}/x
+PATH_WIN=C:\data\$HOME
+ESC="\${HOST}"
+`)
+		defer os.Remove(name)
+
+		t.Log("\tWhen the file is valid.")
+		{
+			m, err := cfg.FileProvider{Filename: name}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := map[string]string{
+				"A":        "1",
+				"HOST":     "localhost",
+				"EMPTY":    "",
+				"SPACED":   "some value",
+				"HASH":     "a#b",
+				"DQ":       "Hello\tWorld\n\"quoted\" $HOME",
+				"SQ":       `No ${HOST} \n`,
+				"MULTI":    "line 1\nline 2",
+				"URL":      "http://localhost:8080/1",
+				"USER":     "bill",
+				"UNKNOWN":  "${CFG_TEST_UNKNOWN}/x",
+				"PATH_WIN": `C:\data$HOME`,
+				"ESC":      "${HOST}",
+			}
+
+			if !reflect.DeepEqual(m, exp) {
+				for k, v := range exp {
+					if m[k] != v {
+						t.Logf("\t\tKey %s : Got %q Exp %q", k, m[k], v)
+					}
+				}
+				t.Errorf("\t\t%s Should parse the dotenv grammar.", failed)
+			} else {
+				t.Logf("\t\t%s Should parse the dotenv grammar.", success)
+			}
+
+			c, err := cfg.New(cfg.FileProvider{Filename: name})
+			if err != nil {
+				t.Fatalf("\t\t%s Should create a Config : %v", failed, err)
+			}
+
+			if c.MustString("SQ") == `No ${HOST} \n` && c.MustString("ESC") == "${HOST}" {
+				t.Logf("\t\t%s Should not resolve literal values in the Config.", success)
+			} else {
+				t.Errorf("\t\t%s Should not resolve literal values in the Config : got %q %q", failed, c.MustString("SQ"), c.MustString("ESC"))
+			}
+		}
+
+		t.Log("\tWhen the file has syntax errors.")
+		{
+			tt := []string{
+				"A=1\nNOEQUALS\n",
+				"1KEY=value\n",
+				"A=\"unterminated\nB=2\n",
+				"A=\"value\" trailing\n",
+			}
+
+			for _, doc := range tt {
+				bad := writeTemp(t, doc)
+				_, err := cfg.FileProvider{Filename: bad}.Provide()
+				os.Remove(bad)
+
+				if err == nil {
+					t.Errorf("\t\t%s Should return an error for %q.", failed, doc)
+				} else {
+					t.Logf("\t\t%s Should return an error for %q : %v", success, doc, err)
+				}
+			}
+		}
+
+		t.Log("\tWhen a Namespace is given.")
+		{
+			ns := writeTemp(t, "APP_HOST=localhost\napp_port=80\nOTHER=x\n")
+			defer os.Remove(ns)
+
+			m, err := cfg.FileProvider{Filename: ns, Namespace: "app"}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if !reflect.DeepEqual(m, map[string]string{"HOST": "localhost"}) {
+				t.Errorf("\t\t%s Should only load the keys in the namespace : %v", failed, m)
+			} else {
+				t.Logf("\t\t%s Should only load the keys in the namespace.", success)
+			}
+		}
+	}
+}
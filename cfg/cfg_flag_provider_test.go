@@ -0,0 +1,148 @@
+package cfg_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestFlagProvider validates the ability to load configuration from command
+// line flags.
+func TestFlagProvider(t *testing.T) {
+	t.Log("Given the need to load configuration from command line flags.")
+	{
+		t.Log("\tWhen scanning the arguments without a Schema.")
+		{
+			m, err := cfg.FlagProvider{
+				Args: []string{"serve", "--db-host=localhost", "-port=80", "--name", "kit", "--verbose", "--debug", "--", "--ignored=1"},
+			}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := map[string]string{"DB_HOST": "localhost", "PORT": "80", "NAME": "kit", "VERBOSE": "true", "DEBUG": "true"}
+			if !reflect.DeepEqual(m, exp) {
+				t.Errorf("\t\t%s Should provide the flags as keys : got %v", failed, m)
+			} else {
+				t.Logf("\t\t%s Should provide the flags as keys.", success)
+			}
+		}
+
+		schema := cfg.Schema{
+			{Name: "DB_HOST", Type: cfg.TypeString, Default: "localhost", Description: "Database host."},
+			{Name: "DB_PORT", Type: cfg.TypeInt, Default: "5432", Description: "Database port."},
+			{Name: "DEBUG", Type: cfg.TypeBool, Description: "Enable debugging."},
+		}
+
+		t.Log("\tWhen registering flags from a Schema.")
+		{
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+			fp := cfg.FlagProvider{
+				Schema:  schema,
+				FlagSet: fs,
+				Args:    []string{"--db-port", "6000", "--debug"},
+			}
+
+			c, err := cfg.New(cfg.MultiProvider{
+				Providers: []cfg.Provider{schema, cfg.MapProvider{Map: map[string]string{"DB_PORT": "7000"}}, fp},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if c.MustInt("DB_PORT") != 6000 || !c.MustBool("DEBUG") || c.MustString("DB_HOST") != "localhost" {
+				t.Errorf("\t\t%s Should override the other Providers : %s", failed, c.Log())
+			} else {
+				t.Logf("\t\t%s Should override the other Providers.", success)
+			}
+
+			if s, _ := c.Source("DB_PORT"); s != "flags" {
+				t.Errorf("\t\t%s Should report the flags as the source : got %q", failed, s)
+			} else {
+				t.Logf("\t\t%s Should report the flags as the source.", success)
+			}
+
+			if f := fs.Lookup("db-host"); f == nil || f.Usage != "Database host." {
+				t.Errorf("\t\t%s Should register a flag with the description as usage.", failed)
+			} else {
+				t.Logf("\t\t%s Should register a flag with the description as usage.", success)
+			}
+
+			if _, err := fp.Provide(); err != nil {
+				t.Errorf("\t\t%s Should be able to provide again : %v", failed, err)
+			} else {
+				t.Logf("\t\t%s Should be able to provide again.", success)
+			}
+		}
+
+		t.Log("\tWhen a flag value doesn't match its type.")
+		{
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(ioutil.Discard)
+
+			_, err := cfg.FlagProvider{
+				Schema:  schema,
+				FlagSet: fs,
+				Args:    []string{"--db-port=abc"},
+			}.Provide()
+			if err == nil {
+				t.Errorf("\t\t%s Should return an error.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error : %v", success, err)
+			}
+		}
+
+		t.Log("\tWhen the FlagSet was parsed before the flags were registered.")
+		{
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.Parse(nil)
+
+			_, err := cfg.FlagProvider{
+				Schema:  schema,
+				FlagSet: fs,
+				Args:    []string{"--db-port=6000"},
+			}.Provide()
+			if err == nil {
+				t.Errorf("\t\t%s Should return an error.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error : %v", success, err)
+			}
+		}
+
+		t.Log("\tWhen no FlagSet is given.")
+		{
+			fp := cfg.FlagProvider{
+				Schema: schema,
+				Args:   []string{"--db-port=6000"},
+			}
+
+			m, err := fp.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if m["DB_PORT"] != "6000" {
+				t.Errorf("\t\t%s Should parse the flags : got %v", failed, m)
+			} else {
+				t.Logf("\t\t%s Should parse the flags.", success)
+			}
+
+			if flag.CommandLine.Lookup("db-port") != nil {
+				t.Errorf("\t\t%s Should not use the command line FlagSet.", failed)
+			} else {
+				t.Logf("\t\t%s Should not use the command line FlagSet.", success)
+			}
+
+			fp.Args = []string{"--db-port=6000", "--verbose"}
+			if _, err := fp.Provide(); err == nil {
+				t.Errorf("\t\t%s Should return an error for an unknown flag.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error for an unknown flag : %v", success, err)
+			}
+		}
+	}
+}
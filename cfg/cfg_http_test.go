@@ -0,0 +1,189 @@
+package cfg_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestEndpoint validates the ability to serve and modify the configuration
+// over HTTP.
+func TestEndpoint(t *testing.T) {
+	t.Log("Given the need to manage configuration over HTTP.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{"DEBUG": "false", "DB_PASS": "hunter2"},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		type entry struct {
+			Value  string `json:"value"`
+			Source string `json:"source"`
+		}
+
+		t.Log("\tWhen requesting the configuration.")
+		{
+			w := httptest.NewRecorder()
+			cfg.Endpoint{Config: c}.ServeHTTP(w, httptest.NewRequest("GET", "/config", nil))
+
+			var m map[string]entry
+			if err := json.NewDecoder(w.Body).Decode(&m); err != nil {
+				t.Fatalf("\t\t%s Should return JSON : %v", failed, err)
+			}
+
+			if w.Code != http.StatusOK || m["DEBUG"] != (entry{Value: "false", Source: "map"}) {
+				t.Errorf("\t\t%s Should return the values with their source : got %d %v", failed, w.Code, m)
+			} else {
+				t.Logf("\t\t%s Should return the values with their source.", success)
+			}
+
+			if m["DB_PASS"].Value == "hunter2" {
+				t.Errorf("\t\t%s Should mask secret values.", failed)
+			} else {
+				t.Logf("\t\t%s Should mask secret values.", success)
+			}
+		}
+
+		t.Log("\tWhen PATCH is not enabled.")
+		{
+			w := httptest.NewRecorder()
+			cfg.Endpoint{Config: c}.ServeHTTP(w, httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":true}`)))
+
+			if w.Code != http.StatusMethodNotAllowed || c.MustBool("DEBUG") {
+				t.Errorf("\t\t%s Should reject the request : got %d", failed, w.Code)
+			} else {
+				t.Logf("\t\t%s Should reject the request.", success)
+			}
+		}
+
+		ep := cfg.Endpoint{
+			Config: c,
+			Schema: cfg.Schema{
+				{Name: "DEBUG", Type: cfg.TypeBool},
+				{Name: "LEVEL", Type: cfg.TypeString, Enum: []string{"dev", "user"}},
+			},
+			Authorize: func(r *http.Request) error {
+				if r.Header.Get("Authorization") != "Bearer ops" {
+					return errors.New("not authorized")
+				}
+				return nil
+			},
+		}
+
+		t.Log("\tWhen PATCH is enabled.")
+		{
+			w := httptest.NewRecorder()
+			ep.ServeHTTP(w, httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":true}`)))
+
+			if w.Code != http.StatusUnauthorized || c.MustBool("DEBUG") {
+				t.Errorf("\t\t%s Should reject unauthorized requests : got %d", failed, w.Code)
+			} else {
+				t.Logf("\t\t%s Should reject unauthorized requests.", success)
+			}
+
+			var changed map[string]string
+			c.OnChange(nil, func(old, new map[string]string) {
+				changed = new
+			})
+
+			r := httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":true,"LEVEL":"dev"}`))
+			r.Header.Set("Authorization", "Bearer ops")
+
+			w = httptest.NewRecorder()
+			ep.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK || !c.MustBool("DEBUG") || c.MustString("LEVEL") != "dev" {
+				t.Errorf("\t\t%s Should set the values : got %d %s", failed, w.Code, w.Body.String())
+			} else {
+				t.Logf("\t\t%s Should set the values.", success)
+			}
+
+			if s, _ := c.Source("DEBUG"); s != cfg.SourceSet || changed["DEBUG"] != "true" {
+				t.Errorf("\t\t%s Should set the values through the Config : got %q %v", failed, s, changed)
+			} else {
+				t.Logf("\t\t%s Should set the values through the Config.", success)
+			}
+
+			r = httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":false,"LEVEL":"trace","OTHER":"1"}`))
+			r.Header.Set("Authorization", "Bearer ops")
+
+			w = httptest.NewRecorder()
+			ep.ServeHTTP(w, r)
+
+			var resp struct {
+				Fields cfg.InvalidError `json:"fields"`
+			}
+			json.NewDecoder(w.Body).Decode(&resp)
+
+			if w.Code != http.StatusBadRequest || len(resp.Fields) != 2 || !c.MustBool("DEBUG") {
+				t.Errorf("\t\t%s Should reject invalid values without setting any : got %d %v", failed, w.Code, resp.Fields)
+			} else {
+				t.Logf("\t\t%s Should reject invalid values without setting any.", success)
+			}
+		}
+
+		t.Log("\tWhen PATCH has no Schema or Writable keys.")
+		{
+			open := cfg.Endpoint{Config: c, Authorize: ep.Authorize}
+
+			r := httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":false}`))
+			r.Header.Set("Authorization", "Bearer ops")
+
+			w := httptest.NewRecorder()
+			open.ServeHTTP(w, r)
+
+			if w.Code != http.StatusMethodNotAllowed || !c.MustBool("DEBUG") {
+				t.Errorf("\t\t%s Should reject the request : got %d", failed, w.Code)
+			} else {
+				t.Logf("\t\t%s Should reject the request.", success)
+			}
+		}
+
+		t.Log("\tWhen a PATCH value holds a reference.")
+		{
+			os.Setenv("CFG_TEST_SECRET", "s3cr3t")
+			defer os.Unsetenv("CFG_TEST_SECRET")
+			c.SetEnvFallback(true)
+
+			wr := cfg.Endpoint{Config: c, Writable: []string{"X", "Y"}, Authorize: ep.Authorize}
+
+			r := httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"X":"${DB_PASS}","Y":"${CFG_TEST_SECRET}"}`))
+			r.Header.Set("Authorization", "Bearer ops")
+
+			w := httptest.NewRecorder()
+			wr.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK || c.MustString("X") != "${DB_PASS}" || c.MustString("Y") != "${CFG_TEST_SECRET}" {
+				t.Errorf("\t\t%s Should store the values literally : got %d %s", failed, w.Code, w.Body.String())
+			} else {
+				t.Logf("\t\t%s Should store the values literally.", success)
+			}
+
+			if body := w.Body.String(); strings.Contains(body, "hunter2") || strings.Contains(body, "s3cr3t") {
+				t.Errorf("\t\t%s Should not return the referenced secrets : %s", failed, body)
+			} else {
+				t.Logf("\t\t%s Should not return the referenced secrets.", success)
+			}
+
+			r = httptest.NewRequest("PATCH", "/config", strings.NewReader(`{"DEBUG":true}`))
+			r.Header.Set("Authorization", "Bearer ops")
+
+			w = httptest.NewRecorder()
+			wr.ServeHTTP(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("\t\t%s Should reject keys that aren't writable : got %d", failed, w.Code)
+			} else {
+				t.Logf("\t\t%s Should reject keys that aren't writable.", success)
+			}
+		}
+	}
+}
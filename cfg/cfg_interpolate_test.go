@@ -0,0 +1,106 @@
+package cfg_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestInterpolate validates the ability to reference other keys in values.
+func TestInterpolate(t *testing.T) {
+	t.Log("Given the need to reference other keys in values.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{
+				"DB_USER": "root",
+				"DB_HOST": "localhost",
+				"DB_PORT": "${DB_PORT_DEFAULT}",
+				"DB_URL":  "postgres://${DB_USER}@${DB_HOST}:${DB_PORT}/app",
+				"LITERAL": "$${DB_USER} costs $5",
+				"TIMEOUT": "${TIMEOUT_MS:-500}ms",
+				"HOME":    "${CFG_TEST_HOME}",
+				"PASS":    "pa$$word",
+
+				"DB_PORT_DEFAULT": "5432",
+			},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		t.Log("\tWhen the Config is created.")
+		{
+			tt := []struct {
+				key string
+				exp string
+			}{
+				{"DB_URL", "postgres://root@localhost:5432/app"},
+				{"LITERAL", "${DB_USER} costs $5"},
+				{"TIMEOUT", "500ms"},
+				{"HOME", "${CFG_TEST_HOME}"},
+				{"PASS", "pa$$word"},
+			}
+
+			for _, tc := range tt {
+				if v := c.MustString(tc.key); v != tc.exp {
+					t.Errorf("\t\t%s Should resolve key %q to %q : got %q", failed, tc.key, tc.exp, v)
+				} else {
+					t.Logf("\t\t%s Should resolve key %q to %q.", success, tc.key, tc.exp)
+				}
+			}
+		}
+
+		t.Log("\tWhen a referenced key is set.")
+		{
+			var changed string
+			c.OnChange([]string{"DB_URL"}, func(o, n map[string]string) {
+				changed = n["DB_URL"]
+			})
+
+			c.SetString("DB_HOST", "db.local")
+
+			exp := "postgres://root@db.local:5432/app"
+			if v := c.MustString("DB_URL"); v != exp || changed != exp {
+				t.Errorf("\t\t%s Should resolve and notify the dependent key : got %q %q", failed, v, changed)
+			} else {
+				t.Logf("\t\t%s Should resolve and notify the dependent key.", success)
+			}
+		}
+
+		t.Log("\tWhen falling back to the process environment.")
+		{
+			os.Setenv("CFG_TEST_HOME", "/home/kit")
+			defer os.Unsetenv("CFG_TEST_HOME")
+
+			if err := c.SetEnvFallback(true); err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if v := c.MustString("HOME"); v != "/home/kit" {
+				t.Errorf("\t\t%s Should resolve from the environment : got %q", failed, v)
+			} else {
+				t.Logf("\t\t%s Should resolve from the environment.", success)
+			}
+		}
+
+		t.Log("\tWhen the values contain a reference cycle.")
+		{
+			_, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{
+					"A": "${B}",
+					"B": "x${C}",
+					"C": "${A}",
+					"D": "ok",
+				},
+			})
+
+			inv, ok := err.(cfg.InvalidError)
+			if !ok || len(inv) != 1 {
+				t.Errorf("\t\t%s Should report the cycle : %v", failed, err)
+			} else {
+				t.Logf("\t\t%s Should report the cycle : %v", success, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cfg_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestMultiProvider validates the ability to layer Providers and track the
+// source of each key.
+func TestMultiProvider(t *testing.T) {
+	t.Log("Given the need to layer configuration from several Providers.")
+	{
+		f, err := ioutil.TempFile("", "cfg")
+		if err != nil {
+			t.Fatalf("\t%s Should be able to create a temp file : %v", failed, err)
+		}
+		defer os.Remove(f.Name())
+
+		f.WriteString("HOST=file.local\nPORT=4000\nNAME=kit\n")
+		f.Close()
+
+		os.Setenv("MULTI_PORT", "5000")
+		defer os.Unsetenv("MULTI_PORT")
+
+		fp := cfg.FileProvider{Filename: f.Name()}
+		ep := cfg.EnvProvider{Namespace: "MULTI"}
+		mp := cfg.MapProvider{Map: map[string]string{"HOST": "map.local"}}
+
+		t.Log("\tWhen the Providers are merged in order.")
+		{
+			c, err := cfg.New(cfg.MultiProvider{
+				Providers: []cfg.Provider{fp, ep, mp},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+			t.Logf("\t\t%s Should not return an error.", success)
+
+			tt := []struct {
+				key    string
+				value  string
+				source string
+			}{
+				{"HOST", "map.local", "map"},
+				{"PORT", "5000", "env:MULTI"},
+				{"NAME", "kit", "file:" + f.Name()},
+			}
+
+			for _, tc := range tt {
+				if v := c.MustString(tc.key); v != tc.value {
+					t.Errorf("\t\t%s Should have key %q with value %q : got %q", failed, tc.key, tc.value, v)
+				} else {
+					t.Logf("\t\t%s Should have key %q with value %q.", success, tc.key, tc.value)
+				}
+
+				if s, _ := c.Source(tc.key); s != tc.source {
+					t.Errorf("\t\t%s Should have key %q from source %q : got %q", failed, tc.key, tc.source, s)
+				} else {
+					t.Logf("\t\t%s Should have key %q from source %q.", success, tc.key, tc.source)
+				}
+			}
+
+			if !strings.Contains(c.Log(), "HOST=map.local (map)\n") {
+				t.Errorf("\t\t%s Should include the source in the log output.", failed)
+			} else {
+				t.Logf("\t\t%s Should include the source in the log output.", success)
+			}
+
+			c.SetString("HOST", "set.local")
+			if s, _ := c.Source("HOST"); s != cfg.SourceSet {
+				t.Errorf("\t\t%s Should report %q as the source after a set : got %q", failed, cfg.SourceSet, s)
+			} else {
+				t.Logf("\t\t%s Should report %q as the source after a set.", success, cfg.SourceSet)
+			}
+
+			if _, err := c.Source("UNKNOWN"); err == nil {
+				t.Errorf("\t\t%s Should return an error for an unknown key.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error for an unknown key.", success)
+			}
+		}
+
+		t.Log("\tWhen one of the Providers fails.")
+		{
+			_, err := cfg.New(cfg.MultiProvider{
+				Providers: []cfg.Provider{mp, cfg.FileProvider{Filename: "/does/not/exist"}},
+			})
+			if err == nil {
+				t.Errorf("\t\t%s Should return an error.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error.", success)
+			}
+		}
+	}
+}
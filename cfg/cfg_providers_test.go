@@ -0,0 +1,257 @@
+package cfg_test
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// writeTemp writes the data to a temp file and returns its name.
+func writeTemp(t *testing.T, data string) string {
+	f, err := ioutil.TempFile("", "cfg")
+	if err != nil {
+		t.Fatalf("\t%s Should be able to create a temp file : %v", failed, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatalf("\t%s Should be able to write a temp file : %v", failed, err)
+	}
+
+	return f.Name()
+}
+
+// TestJSONProvider validates the ability to load a nested JSON document.
+func TestJSONProvider(t *testing.T) {
+	t.Log("Given the need to load configuration from a JSON document.")
+	{
+		name := writeTemp(t, `{
+			"name": "kit",
+			"db": {"host": "localhost", "pool": {"max": 10, "ratio": 0.5}},
+			"tags": ["a", "b,c"],
+			"servers": [{"host": "s1"}, {"host": "s2"}],
+			"debug": true,
+			"none": null
+		}`)
+		defer os.Remove(name)
+
+		t.Log("\tWhen using the default separator.")
+		{
+			m, err := cfg.JSONProvider{Filename: name}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := map[string]string{
+				"name":           "kit",
+				"db.host":        "localhost",
+				"db.pool.max":    "10",
+				"db.pool.ratio":  "0.5",
+				"tags":           `a,"b,c"`,
+				"servers.0.host": "s1",
+				"servers.1.host": "s2",
+				"debug":          "true",
+				"none":           "",
+			}
+
+			if !reflect.DeepEqual(m, exp) {
+				t.Logf("\t\tGot : %v", m)
+				t.Logf("\t\tExp : %v", exp)
+				t.Errorf("\t\t%s Should flatten the document.", failed)
+			} else {
+				t.Logf("\t\t%s Should flatten the document.", success)
+			}
+		}
+
+		t.Log("\tWhen using an uppercase underscore format.")
+		{
+			c, err := cfg.New(cfg.JSONProvider{Filename: name, Separator: "_", Uppercase: true})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if v := c.MustInt("DB_POOL_MAX"); v != 10 {
+				t.Errorf("\t\t%s Should have key %q with value %d : got %d", failed, "DB_POOL_MAX", 10, v)
+			} else {
+				t.Logf("\t\t%s Should have key %q with value %d.", success, "DB_POOL_MAX", 10)
+			}
+
+			if v := c.MustStringSlice("TAGS"); !reflect.DeepEqual(v, []string{"a", "b,c"}) {
+				t.Errorf("\t\t%s Should read the array as a slice : got %v", failed, v)
+			} else {
+				t.Logf("\t\t%s Should read the array as a slice.", success)
+			}
+		}
+	}
+}
+
+// TestTOMLProvider validates the ability to load a TOML or INI document.
+func TestTOMLProvider(t *testing.T) {
+	t.Log("Given the need to load configuration from a TOML document.")
+	{
+		name := writeTemp(t, `# Top level keys.
+name = "kit \"tools\""
+debug = true ; inline comment
+url = http://host/#anchor
+
+[db]
+host = 'C:\data'
+pool.max = 10
+hosts = ["a.local", 'b.local', c.local] # hosts
+
+[db.replica]
+host = replica.local
+
+["site.local"]
+"a.b" = 1
+'x.y'. z = 2
+`)
+		defer os.Remove(name)
+
+		t.Log("\tWhen the document is valid.")
+		{
+			m, err := cfg.TOMLProvider{Filename: name, Separator: "_", Uppercase: true}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := map[string]string{
+				"NAME":             `kit "tools"`,
+				"DEBUG":            "true",
+				"URL":              "http://host/#anchor",
+				"DB_HOST":          `C:\data`,
+				"DB_POOL_MAX":      "10",
+				"DB_HOSTS":         "a.local,b.local,c.local",
+				"DB_REPLICA_HOST":  "replica.local",
+				"SITE.LOCAL_A.B":   "1",
+				"SITE.LOCAL_X.Y_Z": "2",
+			}
+
+			if !reflect.DeepEqual(m, exp) {
+				t.Logf("\t\tGot : %v", m)
+				t.Logf("\t\tExp : %v", exp)
+				t.Errorf("\t\t%s Should flatten the document.", failed)
+			} else {
+				t.Logf("\t\t%s Should flatten the document.", success)
+			}
+		}
+
+		t.Log("\tWhen the document is invalid.")
+		{
+			tt := []string{
+				"name = \"unterminated\n",
+				"[db\n",
+				"key\n",
+				"a = 1\na = 2\n",
+				"list = [1, 2\n",
+				"[[servers]]\n",
+				"[db]\na = 1\n[db]\nb = 2\n",
+				"\"a.b = 1\n",
+			}
+
+			for _, doc := range tt {
+				bad := writeTemp(t, doc)
+				_, err := cfg.TOMLProvider{Filename: bad}.Provide()
+				os.Remove(bad)
+
+				if err == nil {
+					t.Errorf("\t\t%s Should return an error for %q.", failed, doc)
+				} else {
+					t.Logf("\t\t%s Should return an error for %q : %v", success, doc, err)
+				}
+			}
+		}
+	}
+}
+
+// TestYAMLProvider validates the ability to load a YAML document.
+func TestYAMLProvider(t *testing.T) {
+	t.Log("Given the need to load configuration from a YAML document.")
+	{
+		name := writeTemp(t, `---
+# Top level keys.
+name: "kit \"tools\""
+debug: true # inline comment
+url: http://host/#anchor
+empty: ~
+db:
+  host: 'C:\data'
+  pool:
+    max: 10
+  hosts: [a.local, 'b.local', "c,local"]
+tags:
+- a
+- 'it''s'
+servers:
+  - host: s1
+    port: 1
+  - host: s2
+"site.local":
+  a: 1
+`)
+		defer os.Remove(name)
+
+		t.Log("\tWhen the document is valid.")
+		{
+			m, err := cfg.YAMLProvider{Filename: name, Separator: "_", Uppercase: true}.Provide()
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			exp := map[string]string{
+				"NAME":           `kit "tools"`,
+				"DEBUG":          "true",
+				"URL":            "http://host/#anchor",
+				"EMPTY":          "",
+				"DB_HOST":        `C:\data`,
+				"DB_POOL_MAX":    "10",
+				"DB_HOSTS":       `a.local,b.local,"c,local"`,
+				"TAGS":           "a,it's",
+				"SERVERS_0_HOST": "s1",
+				"SERVERS_0_PORT": "1",
+				"SERVERS_1_HOST": "s2",
+				"SITE.LOCAL_A":   "1",
+			}
+
+			if !reflect.DeepEqual(m, exp) {
+				t.Logf("\t\tGot : %v", m)
+				t.Logf("\t\tExp : %v", exp)
+				t.Errorf("\t\t%s Should flatten the document.", failed)
+			} else {
+				t.Logf("\t\t%s Should flatten the document.", success)
+			}
+		}
+
+		t.Log("\tWhen the document is invalid.")
+		{
+			tt := []string{
+				"name: \"unterminated\n",
+				"key\n",
+				"a: 1\na: 2\n",
+				"list: [1, 2\n",
+				"db:\n  host: a\n    port: 1\n",
+				"db:\n\thost: a\n",
+				"cert: |\n  line\n",
+				"base: &base 1\n",
+				"db: {host: a}\n",
+				"a: 1\n---\nb: 2\n",
+				"- a\n",
+			}
+
+			for _, doc := range tt {
+				bad := writeTemp(t, doc)
+				_, err := cfg.YAMLProvider{Filename: bad}.Provide()
+				os.Remove(bad)
+
+				if err == nil {
+					t.Errorf("\t\t%s Should return an error for %q.", failed, doc)
+				} else {
+					t.Logf("\t\t%s Should return an error for %q : %v", success, doc, err)
+				}
+			}
+		}
+	}
+}
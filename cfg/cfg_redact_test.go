@@ -0,0 +1,122 @@
+package cfg_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestRedact validates the ability to mask secret values.
+func TestRedact(t *testing.T) {
+	t.Log("Given the need to mask secret values when logging.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{
+				"HOST":        "db.local",
+				"DB_PASSWORD": "hunter2",
+				"API_KEY":     "abcdef",
+				"AUTH_TOKEN":  "xyz",
+				"SESSION_ID":  "s3cr3t",
+			},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		t.Log("\tWhen using the default policy.")
+		{
+			exp := "API_KEY=[REDACTED len=6] (map)\n" +
+				"AUTH_TOKEN=[REDACTED len=3] (map)\n" +
+				"DB_PASSWORD=[REDACTED len=7] (map)\n" +
+				"HOST=db.local (map)\n" +
+				"SESSION_ID=s3cr3t (map)\n"
+
+			if got := c.Log(); got != exp {
+				t.Logf("\t\tGot :\n%s", got)
+				t.Logf("\t\tExp :\n%s", exp)
+				t.Errorf("\t\t%s Should log sorted keys with secrets masked.", failed)
+			} else {
+				t.Logf("\t\t%s Should log sorted keys with secrets masked.", success)
+			}
+		}
+
+		t.Log("\tWhen using a custom policy.")
+		{
+			c.SetRedactor(cfg.Redactor{
+				Patterns: []*regexp.Regexp{regexp.MustCompile(`^SESSION_`)},
+				Keys:     []string{"HOST"},
+				Mask:     cfg.MaskHash,
+			})
+
+			m := c.Masked()
+
+			if m["API_KEY"] != "abcdef" {
+				t.Errorf("\t\t%s Should not mask keys outside the policy : got %q", failed, m["API_KEY"])
+			} else {
+				t.Logf("\t\t%s Should not mask keys outside the policy.", success)
+			}
+
+			if exp := cfg.MaskHash("s3cr3t"); m["SESSION_ID"] != exp || m["SESSION_ID"] == "s3cr3t" {
+				t.Errorf("\t\t%s Should mask keys matching a pattern : got %q", failed, m["SESSION_ID"])
+			} else {
+				t.Logf("\t\t%s Should mask keys matching a pattern.", success)
+			}
+
+			if m["HOST"] == "db.local" {
+				t.Errorf("\t\t%s Should mask keys listed explicitly.", failed)
+			} else {
+				t.Logf("\t\t%s Should mask keys listed explicitly.", success)
+			}
+		}
+
+		t.Log("\tWhen a value references a secret.")
+		{
+			c, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{
+					"DB_PASS": "hunter2",
+					"DB_URL":  "postgres://u:${DB_PASS}@h/app",
+				},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			var buf bytes.Buffer
+			if err := c.Export(&buf, cfg.FormatDotenv); err != nil {
+				t.Fatalf("\t\t%s Should be able to export : %v", failed, err)
+			}
+
+			sc, err := cfg.New(c.Snapshot())
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			outputs := map[string]string{
+				"Log":      c.Log(),
+				"Masked":   c.Masked()["DB_URL"],
+				"Export":   buf.String(),
+				"Snapshot": c.Snapshot().Masked()["DB_URL"],
+				"Config":   sc.Log(),
+				"Diff":     fmt.Sprint(c.Diff(&cfg.Config{})),
+			}
+
+			for name, out := range outputs {
+				if strings.Contains(out, "hunter2") {
+					t.Errorf("\t\t%s Should mask the referencing value in %s : %s", failed, name, out)
+				} else {
+					t.Logf("\t\t%s Should mask the referencing value in %s.", success, name)
+				}
+			}
+
+			if v := c.MustString("DB_URL"); v != "postgres://u:hunter2@h/app" {
+				t.Errorf("\t\t%s Should resolve the value : got %q", failed, v)
+			} else {
+				t.Logf("\t\t%s Should resolve the value.", success)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cfg_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestOr validates the ability to read values with a default.
+func TestOr(t *testing.T) {
+	t.Log("Given the need to read values with a default.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{"PORT": "4000", "TIMEOUT": "bad"},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		t.Log("\tWhen reading keys that are found, missing or malformed.")
+		{
+			if v := c.IntOr("PORT", 80); v != 4000 {
+				t.Errorf("\t\t%s Should return the value when found : got %d", failed, v)
+			} else {
+				t.Logf("\t\t%s Should return the value when found.", success)
+			}
+
+			if v := c.StringOr("HOST", "localhost"); v != "localhost" {
+				t.Errorf("\t\t%s Should return the default when missing : got %q", failed, v)
+			} else {
+				t.Logf("\t\t%s Should return the default when missing.", success)
+			}
+
+			if v := c.DurationOr("TIMEOUT", time.Second); v != time.Second {
+				t.Errorf("\t\t%s Should return the default when malformed : got %v", failed, v)
+			} else {
+				t.Logf("\t\t%s Should return the default when malformed.", success)
+			}
+		}
+	}
+}
+
+// TestSchema validates the ability to provide defaults and validate a Config
+// from a Schema.
+func TestSchema(t *testing.T) {
+	t.Log("Given the need to declare configuration with a Schema.")
+	{
+		schema := cfg.Schema{
+			{Name: "PORT", Type: cfg.TypeInt, Default: "8080", Min: "1", Max: "65535", Description: "Port to listen on."},
+			{Name: "MODE", Type: cfg.TypeString, Required: true, Enum: []string{"dev", "prod"}, Description: "Run mode."},
+			{Name: "TIMEOUT", Type: cfg.TypeDuration, Default: "5s", Min: "1s", Description: "Request timeout."},
+			{Name: "BUFFER", Type: cfg.TypeByteSize, Max: "1GiB", Description: "Buffer size."},
+			{Name: "DEBUG", Type: cfg.TypeBool, Description: "Enable debugging."},
+		}
+
+		t.Log("\tWhen the configuration is valid.")
+		{
+			c, err := cfg.New(cfg.MultiProvider{
+				Providers: []cfg.Provider{
+					schema,
+					cfg.MapProvider{Map: map[string]string{"MODE": "prod", "BUFFER": "64MiB"}},
+				},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			if err := c.Validate(schema); err != nil {
+				t.Errorf("\t\t%s Should validate : %v", failed, err)
+			} else {
+				t.Logf("\t\t%s Should validate.", success)
+			}
+
+			if c.MustInt("PORT") != 8080 {
+				t.Errorf("\t\t%s Should provide the default for %q.", failed, "PORT")
+			} else {
+				t.Logf("\t\t%s Should provide the default for %q.", success, "PORT")
+			}
+
+			if s, _ := c.Source("PORT"); s != "schema" {
+				t.Errorf("\t\t%s Should report the schema as the source of %q : got %q", failed, "PORT", s)
+			} else {
+				t.Logf("\t\t%s Should report the schema as the source of %q.", success, "PORT")
+			}
+		}
+
+		t.Log("\tWhen the configuration has violations.")
+		{
+			c, err := cfg.New(cfg.MapProvider{
+				Map: map[string]string{
+					"PORT":    "70000",
+					"TIMEOUT": "10ms",
+					"BUFFER":  "2GiB",
+					"DEBUG":   "maybe",
+				},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error creating the Config : %v", failed, err)
+			}
+
+			inv, ok := c.Validate(schema).(cfg.InvalidError)
+			if !ok {
+				t.Fatalf("\t\t%s Should return an InvalidError.", failed)
+			}
+
+			if len(inv) != len(schema) {
+				t.Errorf("\t\t%s Should report every violation : %v", failed, inv)
+			} else {
+				t.Logf("\t\t%s Should report every violation.", success)
+			}
+		}
+
+		t.Log("\tWhen printing the usage.")
+		{
+			usage := schema.Usage()
+
+			for _, s := range []string{"PORT", "1..65535", "(required)", "dev|prod", "Request timeout."} {
+				if !strings.Contains(usage, s) {
+					t.Errorf("\t\t%s Should include %q in the usage :\n%s", failed, s, usage)
+				} else {
+					t.Logf("\t\t%s Should include %q in the usage.", success, s)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cfg_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestSub validates the ability to work with a view of a Config.
+func TestSub(t *testing.T) {
+	t.Log("Given the need to pass a subset of the configuration around.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{
+				"DB_HOST":      "localhost",
+				"DB_PORT":      "5432",
+				"DB_POOL_SIZE": "10",
+				"DB_PASS":      "hunter2",
+				"TCP_PORT":     "9000",
+			},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		db := c.Sub("DB_")
+
+		t.Log("\tWhen reading through a view.")
+		{
+			if db.MustString("HOST") != "localhost" || db.MustInt("PORT") != 5432 {
+				t.Errorf("\t\t%s Should read the keys without the prefix.", failed)
+			} else {
+				t.Logf("\t\t%s Should read the keys without the prefix.", success)
+			}
+
+			exp := []string{"HOST", "PASS", "POOL_SIZE", "PORT"}
+			if keys := db.Keys(); !reflect.DeepEqual(keys, exp) {
+				t.Errorf("\t\t%s Should only list the keys with the prefix : got %v", failed, keys)
+			} else {
+				t.Logf("\t\t%s Should only list the keys with the prefix.", success)
+			}
+
+			if !db.Has("HOST") || db.Has("TCP_PORT") || !c.Has("TCP_PORT") {
+				t.Errorf("\t\t%s Should report which keys are found.", failed)
+			} else {
+				t.Logf("\t\t%s Should report which keys are found.", success)
+			}
+
+			if db.Sub("POOL_").MustInt("SIZE") != 10 {
+				t.Errorf("\t\t%s Should be able to narrow the view.", failed)
+			} else {
+				t.Logf("\t\t%s Should be able to narrow the view.", success)
+			}
+
+			if log := db.Log(); strings.Contains(log, "hunter2") || !strings.Contains(log, "HOST=localhost (map)") {
+				t.Errorf("\t\t%s Should log the view with secrets masked : got\n%s", failed, log)
+			} else {
+				t.Logf("\t\t%s Should log the view with secrets masked.", success)
+			}
+		}
+
+		t.Log("\tWhen writing through a view.")
+		{
+			var old, new map[string]string
+			db.OnChange([]string{"PORT"}, func(o, n map[string]string) {
+				old, new = o, n
+			})
+
+			db.SetInt("PORT", 6000)
+
+			if c.MustInt("DB_PORT") != 6000 {
+				t.Errorf("\t\t%s Should update the Config.", failed)
+			} else {
+				t.Logf("\t\t%s Should update the Config.", success)
+			}
+
+			if old["PORT"] != "5432" || new["PORT"] != "6000" {
+				t.Errorf("\t\t%s Should notify the view's subscribers without the prefix : %v %v", failed, old, new)
+			} else {
+				t.Logf("\t\t%s Should notify the view's subscribers without the prefix.", success)
+			}
+
+			c.SetString("DB_NAME", "kit")
+
+			if db.MustString("NAME") != "kit" {
+				t.Errorf("\t\t%s Should see keys set on the Config.", failed)
+			} else {
+				t.Logf("\t\t%s Should see keys set on the Config.", success)
+			}
+
+			if err := db.Reload(cfg.MapProvider{}); err == nil {
+				t.Errorf("\t\t%s Should not be able to reload a view.", failed)
+			} else {
+				t.Logf("\t\t%s Should not be able to reload a view.", success)
+			}
+		}
+	}
+}
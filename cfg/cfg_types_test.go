@@ -0,0 +1,119 @@
+package cfg_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestTypes validates the ability to read and set the extended set of types.
+func TestTypes(t *testing.T) {
+	t.Log("Given the need to read and set extended types.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{
+				"RATIO":    "0.75",
+				"BIG":      "9223372036854775807",
+				"COUNT":    "42",
+				"BUFFER":   "64MiB",
+				"DISK":     "1.5GB",
+				"HOSTS":    `a.local, "b,c.local", "say ""hi"""`,
+				"PORTS":    "80, 443,8080",
+				"BACKOFF":  "1s,5s, 1m",
+				"LABELS":   "env:prod, team:core,url:http://x",
+				"BAD_SIZE": "12 parsecs",
+				"BAD_MAP":  "novalue",
+			},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		t.Log("\tWhen reading values from the Config.")
+		{
+			tt := []struct {
+				key string
+				got interface{}
+				exp interface{}
+			}{
+				{"RATIO", c.MustFloat64("RATIO"), 0.75},
+				{"BIG", c.MustInt64("BIG"), int64(9223372036854775807)},
+				{"COUNT", c.MustUint("COUNT"), uint(42)},
+				{"BUFFER", c.MustByteSize("BUFFER"), uint64(64 << 20)},
+				{"DISK", c.MustByteSize("DISK"), uint64(1500000000)},
+				{"HOSTS", c.MustStringSlice("HOSTS"), []string{"a.local", "b,c.local", `say "hi"`}},
+				{"PORTS", c.MustIntSlice("PORTS"), []int{80, 443, 8080}},
+				{"BACKOFF", c.MustDurationSlice("BACKOFF"), []time.Duration{time.Second, 5 * time.Second, time.Minute}},
+				{"LABELS", c.MustStringMap("LABELS"), map[string]string{"env": "prod", "team": "core", "url": "http://x"}},
+			}
+
+			for _, tc := range tt {
+				if !reflect.DeepEqual(tc.got, tc.exp) {
+					t.Errorf("\t\t%s Should have key %q with value %v : got %v", failed, tc.key, tc.exp, tc.got)
+				} else {
+					t.Logf("\t\t%s Should have key %q with value %v.", success, tc.key, tc.exp)
+				}
+			}
+
+			if _, err := c.ByteSize("BAD_SIZE"); err == nil {
+				t.Errorf("\t\t%s Should return an error for an invalid byte size.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error for an invalid byte size.", success)
+			}
+
+			if _, err := c.StringMap("BAD_MAP"); err == nil {
+				t.Errorf("\t\t%s Should return an error for an invalid map.", failed)
+			} else {
+				t.Logf("\t\t%s Should return an error for an invalid map.", success)
+			}
+
+			shouldPanic(t, "MISSING", func() {
+				c.MustStringSlice("MISSING")
+			})
+		}
+
+		t.Log("\tWhen setting values.")
+		{
+			c.SetFloat64("F", 1.25)
+			c.SetInt64("I", -7)
+			c.SetUint("U", 7)
+			c.SetByteSize("B", 3<<30)
+			c.SetStringSlice("S", []string{"x", "y,z", ` "q"`})
+			c.SetIntSlice("IS", []int{1, 2})
+			c.SetDurationSlice("DS", []time.Duration{time.Millisecond})
+			c.SetStringMap("M", map[string]string{"b": "2", "a": "1,1"})
+
+			tt := []struct {
+				key string
+				got interface{}
+				exp interface{}
+				str string
+			}{
+				{"F", c.MustFloat64("F"), 1.25, "1.25"},
+				{"I", c.MustInt64("I"), int64(-7), "-7"},
+				{"U", c.MustUint("U"), uint(7), "7"},
+				{"B", c.MustByteSize("B"), uint64(3 << 30), "3GiB"},
+				{"S", c.MustStringSlice("S"), []string{"x", "y,z", ` "q"`}, `x,"y,z"," ""q"""`},
+				{"IS", c.MustIntSlice("IS"), []int{1, 2}, "1,2"},
+				{"DS", c.MustDurationSlice("DS"), []time.Duration{time.Millisecond}, "1ms"},
+				{"M", c.MustStringMap("M"), map[string]string{"a": "1,1", "b": "2"}, `"a:1,1",b:2`},
+			}
+
+			for _, tc := range tt {
+				if !reflect.DeepEqual(tc.got, tc.exp) {
+					t.Errorf("\t\t%s Should return the value %v that was set : got %v", failed, tc.exp, tc.got)
+				} else {
+					t.Logf("\t\t%s Should return the value %v that was set.", success, tc.exp)
+				}
+
+				if str := c.MustString(tc.key); str != tc.str {
+					t.Errorf("\t\t%s Should store key %q as %q : got %q", failed, tc.key, tc.str, str)
+				} else {
+					t.Logf("\t\t%s Should store key %q as %q.", success, tc.key, tc.str)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package cfg_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+// TestReload validates the ability to reload a Config and be notified of the
+// keys that changed.
+func TestReload(t *testing.T) {
+	t.Log("Given the need to reload configuration.")
+	{
+		c, err := cfg.New(cfg.MapProvider{
+			Map: map[string]string{"MIN": "1", "MAX": "10", "NAME": "kit"},
+		})
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		var old, new map[string]string
+		c.OnChange([]string{"MIN", "MAX", "EXTRA"}, func(o, n map[string]string) {
+			old, new = o, n
+		})
+
+		t.Log("\tWhen the Provider returns different values.")
+		{
+			err := c.Reload(cfg.MapProvider{
+				Map: map[string]string{"MIN": "1", "NAME": "other", "EXTRA": "yes", "MAX": "20"},
+			})
+			if err != nil {
+				t.Fatalf("\t\t%s Should not return an error : %v", failed, err)
+			}
+
+			if c.MustInt("MAX") != 20 {
+				t.Errorf("\t\t%s Should see the new value for %q.", failed, "MAX")
+			} else {
+				t.Logf("\t\t%s Should see the new value for %q.", success, "MAX")
+			}
+
+			if len(old) != 1 || old["MAX"] != "10" {
+				t.Errorf("\t\t%s Should receive only the old subscribed values that changed : %v", failed, old)
+			} else {
+				t.Logf("\t\t%s Should receive only the old subscribed values that changed.", success)
+			}
+
+			if len(new) != 2 || new["MAX"] != "20" || new["EXTRA"] != "yes" {
+				t.Errorf("\t\t%s Should receive only the new subscribed values that changed : %v", failed, new)
+			} else {
+				t.Logf("\t\t%s Should receive only the new subscribed values that changed.", success)
+			}
+		}
+
+		t.Log("\tWhen a subscribed key is set.")
+		{
+			old, new = nil, nil
+			c.SetInt("MIN", 5)
+
+			if old["MIN"] != "1" || new["MIN"] != "5" {
+				t.Errorf("\t\t%s Should be notified of the change : %v %v", failed, old, new)
+			} else {
+				t.Logf("\t\t%s Should be notified of the change.", success)
+			}
+		}
+	}
+}
+
+// TestWatch validates the ability to watch a file for changes.
+func TestWatch(t *testing.T) {
+	t.Log("Given the need to watch a configuration file.")
+	{
+		f, err := ioutil.TempFile("", "cfg")
+		if err != nil {
+			t.Fatalf("\t%s Should be able to create a temp file : %v", failed, err)
+		}
+		defer os.Remove(f.Name())
+
+		f.WriteString("RATE=1s\n")
+		f.Close()
+
+		fp := cfg.FileProvider{Filename: f.Name()}
+
+		c, err := cfg.New(fp)
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		changed := make(chan string, 1)
+		c.OnChange([]string{"RATE"}, func(o, n map[string]string) {
+			changed <- n["RATE"]
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- c.Watch(ctx, fp, 10*time.Millisecond)
+		}()
+
+		t.Log("\tWhen the file is modified.")
+		{
+			if err := ioutil.WriteFile(f.Name(), []byte("RATE=2s\n"), 0644); err != nil {
+				t.Fatalf("\t\t%s Should be able to write the file : %v", failed, err)
+			}
+
+			select {
+			case v := <-changed:
+				if v != "2s" {
+					t.Errorf("\t\t%s Should be notified of the new value : got %q", failed, v)
+				} else {
+					t.Logf("\t\t%s Should be notified of the new value.", success)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("\t\t%s Should be notified of the new value.", failed)
+			}
+
+			if c.MustDuration("RATE") != 2*time.Second {
+				t.Errorf("\t\t%s Should see the new value.", failed)
+			} else {
+				t.Logf("\t\t%s Should see the new value.", success)
+			}
+		}
+
+		t.Log("\tWhen the context is canceled.")
+		{
+			cancel()
+
+			if err := <-done; err != context.Canceled {
+				t.Errorf("\t\t%s Should return the context error : %v", failed, err)
+			} else {
+				t.Logf("\t\t%s Should return the context error.", success)
+			}
+		}
+	}
+}
+
+// TestWatchUnchanged validates that watching a Provider that can't be
+// fingerprinted keeps the current values while the configuration is the same.
+func TestWatchUnchanged(t *testing.T) {
+	t.Log("Given the need to watch a Provider that is read on every poll.")
+	{
+		mp := cfg.MapProvider{Map: map[string]string{"TOGGLE": "off", "NAME": "kit"}}
+
+		c, err := cfg.New(mp)
+		if err != nil {
+			t.Fatalf("\t%s Should not return an error creating the Config : %v", failed, err)
+		}
+
+		changed := make(chan string, 1)
+		c.OnChange([]string{"TOGGLE", "NAME"}, func(o, n map[string]string) {
+			changed <- n["TOGGLE"]
+		})
+
+		t.Log("\tWhen a value is set and the configuration is the same.")
+		{
+			c.SetString("TOGGLE", "on")
+			<-changed
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			if err := c.Watch(ctx, mp, 10*time.Millisecond); err != context.DeadlineExceeded {
+				t.Errorf("\t\t%s Should return the context error : %v", failed, err)
+			}
+
+			if v := c.MustString("TOGGLE"); v != "on" {
+				t.Errorf("\t\t%s Should keep the value that was set : got %q", failed, v)
+			} else {
+				t.Logf("\t\t%s Should keep the value that was set.", success)
+			}
+
+			select {
+			case v := <-changed:
+				t.Errorf("\t\t%s Should not be notified : got %q", failed, v)
+			default:
+				t.Logf("\t\t%s Should not be notified.", success)
+			}
+		}
+
+		t.Log("\tWhen watching a view.")
+		{
+			done := make(chan error, 1)
+			go func() {
+				done <- c.Sub("TOG").Watch(context.Background(), mp, 10*time.Millisecond)
+			}()
+
+			select {
+			case err := <-done:
+				if err == nil {
+					t.Errorf("\t\t%s Should return an error at once.", failed)
+				} else {
+					t.Logf("\t\t%s Should return an error at once.", success)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("\t\t%s Should return an error at once.", failed)
+			}
+		}
+	}
+}
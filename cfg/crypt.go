@@ -0,0 +1,234 @@
+package cfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// EncryptedPrefix marks a value as encrypted. The rest of the value is the
+// base64 encoded ciphertext.
+const EncryptedPrefix = "enc:v1:"
+
+// Encrypter is implemented by backends that can encrypt values. The
+// additional data is authenticated but not encrypted, a Config passes the key
+// the value is stored under.
+type Encrypter interface {
+	Encrypt(plaintext []byte, additionalData []byte) ([]byte, error)
+}
+
+// Decrypter is implemented by backends that can decrypt values. Decryption
+// must fail when the additional data isn't the one the value was encrypted
+// with.
+type Decrypter interface {
+	Decrypt(ciphertext []byte, additionalData []byte) ([]byte, error)
+}
+
+// KeyProvider is implemented to provide the key used by a cipher.
+type KeyProvider interface {
+	Key() ([]byte, error)
+}
+
+// KeyFile provides a base64 encoded key stored in a file.
+type KeyFile struct {
+	Filename string
+}
+
+// Key implements the KeyProvider interface.
+func (kf KeyFile) Key() ([]byte, error) {
+	data, err := ioutil.ReadFile(kf.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeKey(string(data))
+}
+
+// KeyEnv provides a base64 encoded key stored in an environment variable.
+type KeyEnv struct {
+	Name string
+}
+
+// Key implements the KeyProvider interface.
+func (ke KeyEnv) Key() ([]byte, error) {
+	value, found := os.LookupEnv(ke.Name)
+	if !found {
+		return nil, fmt.Errorf("environment variable %s not found", ke.Name)
+	}
+
+	return decodeKey(value)
+}
+
+// decodeKey decodes a base64 encoded key.
+func decodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64 encoded : %v", err)
+	}
+
+	return key, nil
+}
+
+// NewKey returns a random base64 encoded 256 bit key for use with AESGCM.
+func NewKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// AESGCM encrypts and decrypts values using AES in Galois/Counter Mode. The
+// random nonce is stored in front of the ciphertext.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM using the key from the KeyProvider. The key must
+// be 16, 24 or 32 bytes long.
+func NewAESGCM(kp KeyProvider) (*AESGCM, error) {
+	key, err := kp.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt implements the Encrypter interface.
+func (a *AESGCM) Encrypt(plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return a.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt implements the Decrypter interface.
+func (a *AESGCM) Decrypt(ciphertext []byte, additionalData []byte) ([]byte, error) {
+	ns := a.aead.NonceSize()
+	if len(ciphertext) < ns {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	return a.aead.Open(nil, ciphertext[:ns], ciphertext[ns:], additionalData)
+}
+
+// EncryptValue encrypts the plaintext for the key and returns it as an
+// encrypted value that a Config can decrypt. The key is bound to the value,
+// so it can't be decrypted when it is stored under another key.
+func EncryptValue(e Encrypter, key string, plaintext string) (string, error) {
+	ciphertext, err := e.Encrypt([]byte(plaintext), []byte(key))
+	if err != nil {
+		return "", err
+	}
+
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptValue decrypts a value created by EncryptValue for the key. Values
+// without the EncryptedPrefix are returned unchanged.
+func DecryptValue(d Decrypter, key string, value string) (string, error) {
+	if !strings.HasPrefix(value, EncryptedPrefix) {
+		return value, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is not base64 encoded : %v", err)
+	}
+
+	plaintext, err := d.Decrypt(ciphertext, []byte(key))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// SetDecrypter sets the Decrypter used to decrypt values with the
+// EncryptedPrefix. The values are resolved again and any changes are
+// delivered to subscribers. Decrypted values, and the values that reference
+// them, are always masked when the Config is logged or exported. It will
+// return an InvalidError listing the keys that could not be decrypted, these
+// keys keep their encrypted value.
+func (c *Config) SetDecrypter(d Decrypter) error {
+	if c.parent != nil {
+		return c.parent.SetDecrypter(d)
+	}
+
+	c.mu.Lock()
+	c.dec = d
+
+	m, err := resolve(c.raw, c.env, c.dec)
+	old := c.m
+	c.m = m
+	subs := c.subs
+	c.mu.Unlock()
+
+	notify(subs, old, m)
+
+	return err
+}
+
+// decrypt returns a copy of the raw values with every encrypted value
+// decrypted. A ${ in a decrypted value is escaped so the plaintext is kept as
+// is when the references are resolved. Keys that can't be decrypted keep
+// their encrypted value and are reported in the returned InvalidError.
+func decrypt(raw map[string]string, d Decrypter) (map[string]string, InvalidError) {
+	m := make(map[string]string, len(raw))
+
+	var inv InvalidError
+	for k, v := range raw {
+		dv, err := DecryptValue(d, k, v)
+		switch {
+		case err != nil:
+			inv = append(inv, Invalid{Key: k, Err: "decrypt : " + err.Error()})
+			dv = v
+		case strings.HasPrefix(v, EncryptedPrefix):
+			dv = strings.Replace(dv, "${", "$${", -1)
+		}
+		m[k] = dv
+	}
+
+	return m, inv
+}
+
+// resolve decrypts the raw values when a Decrypter is given and then resolves
+// the references between them.
+func resolve(raw map[string]string, env bool, d Decrypter) (map[string]string, error) {
+	var inv InvalidError
+	if d != nil {
+		raw, inv = decrypt(raw, d)
+	}
+
+	m, err := interpolate(raw, env)
+	if ierr, ok := err.(InvalidError); ok {
+		inv = append(inv, ierr...)
+	}
+
+	if inv != nil {
+		return m, inv
+	}
+
+	return m, nil
+}
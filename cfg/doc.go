@@ -18,4 +18,13 @@
 //  	proc := cfg.MustString("proc_id")
 //  	port := cfg.MustInt("port")
 //  	ms := cfg.MustTime("stamp")
+//
+// To populate a struct in one call, tag its fields with the keys to read and
+// call Bind:
+//
+//  	var app struct {
+//  		Host string `cfg:"HOST" default:"localhost"`
+//  		Port int    `cfg:"PORT" required:"true"`
+//  	}
+//  	err := cfg.Bind(&app)
 package cfg
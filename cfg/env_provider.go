@@ -13,6 +13,12 @@ type EnvProvider struct {
 	Namespace string
 }
 
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (ep EnvProvider) String() string {
+	return "env:" + ep.Namespace
+}
+
 // Provide implements the Provider interface.
 func (ep EnvProvider) Provide() (map[string]string, error) {
 
@@ -25,17 +31,19 @@ func (ep EnvProvider) Provide() (map[string]string, error) {
 		return nil, errors.New("No environment variables found")
 	}
 
-	// Create the uppercase version to meet the standard {NAMESPACE_} format.
-	uspace := fmt.Sprintf("%s_", strings.ToUpper(ep.Namespace))
-
 	// Loop and match each variable using the uppercase namespace.
 	for _, val := range envs {
-		if !strings.HasPrefix(val, uspace) {
+		idx := strings.Index(val, "=")
+		if idx == -1 {
 			continue
 		}
 
-		idx := strings.Index(val, "=")
-		config[strings.ToUpper(strings.TrimPrefix(val[0:idx], uspace))] = val[idx+1:]
+		key, ok := namespaceKey(val[0:idx], ep.Namespace)
+		if !ok {
+			continue
+		}
+
+		config[key] = val[idx+1:]
 	}
 
 	// Did we find any keys for this namespace?
@@ -45,3 +53,16 @@ func (ep EnvProvider) Provide() (map[string]string, error) {
 
 	return config, nil
 }
+
+// namespaceKey matches the key against the uppercase version of the namespace
+// to meet the standard {NAMESPACE_} format. It returns the key with the prefix
+// removed and made uppercase, or false if the key is not in the namespace.
+func namespaceKey(key, namespace string) (string, bool) {
+	uspace := fmt.Sprintf("%s_", strings.ToUpper(namespace))
+
+	if !strings.HasPrefix(key, uspace) {
+		return "", false
+	}
+
+	return strings.ToUpper(strings.TrimPrefix(key, uspace)), true
+}
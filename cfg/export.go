@@ -0,0 +1,269 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is a format the Config can be exported to.
+type Format string
+
+// Set of formats supported by Export.
+const (
+	FormatDotenv Format = "dotenv"
+	FormatJSON   Format = "json"
+	FormatGo     Format = "go"
+)
+
+// Export writes the configuration to w in the given format with the keys
+// sorted. Secret values are masked according to the Config's Redactor along
+// with any values that were encrypted, use SetRedactor with an empty Redactor
+// to export every other value as is. FormatDotenv can be read back with a
+// FileProvider, FormatJSON writes a single JSON object and FormatGo writes a
+// MapProvider literal that can be pasted into Go source. A literal ${ in a
+// value is written as $${ in JSON and Go so it isn't resolved when read back.
+func (c *Config) Export(w io.Writer, f Format) error {
+	m := c.Masked()
+	if f != FormatDotenv {
+		for k, v := range m {
+			m[k] = strings.Replace(v, "${", "$${", -1)
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+
+	switch f {
+	case FormatDotenv:
+		for _, k := range keys {
+			buf.WriteString(k + "=" + quoteDotenv(m[k]) + "\n")
+		}
+
+	case FormatJSON:
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+
+	case FormatGo:
+		buf.WriteString("cfg.MapProvider{\nMap: map[string]string{\n")
+		for _, k := range keys {
+			buf.WriteString(strconv.Quote(k) + ": " + strconv.Quote(m[k]) + ",\n")
+		}
+		buf.WriteString("},\n}\n")
+
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		buf.Reset()
+		buf.Write(src)
+
+	default:
+		return fmt.Errorf("unknown format %s", f)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// quoteDotenv returns the value quoted for a dotenv file when it contains
+// anything other than plain characters.
+func quoteDotenv(value string) string {
+	plain := value != ""
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if !isNameByte(b, false) && !strings.ContainsRune("./:@,+-=", rune(b)) {
+			plain = false
+			break
+		}
+	}
+
+	if plain {
+		return value
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// Snapshot is an immutable copy of the configuration taken at a point in
+// time. A Snapshot is also a Provider, so a Config can be created from it.
+// The keys that held secrets when the Snapshot was taken stay masked in the
+// Snapshot and in a Config created from it.
+type Snapshot struct {
+	m      map[string]string
+	src    map[string]string
+	masked map[string]string
+	secret map[string]bool
+}
+
+// Snapshot returns a copy of the current configuration that isn't affected by
+// later changes to the Config.
+func (c *Config) Snapshot() Snapshot {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s := Snapshot{
+		m:      make(map[string]string),
+		src:    make(map[string]string),
+		masked: make(map[string]string),
+		secret: make(map[string]bool),
+	}
+
+	red := r.redactor()
+	for _, k := range r.sortedKeys(prefix) {
+		name := strings.TrimPrefix(k, prefix)
+		s.m[name] = r.m[k]
+		s.src[name] = r.src[k]
+		s.masked[name] = r.redactValue(red, k)
+		if r.tainted(red, k, make(map[string]bool)) {
+			s.secret[name] = true
+		}
+	}
+
+	return s
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (s Snapshot) String() string {
+	return "snapshot"
+}
+
+// Provide implements the Provider interface.
+func (s Snapshot) Provide() (map[string]string, error) {
+	return s.Map(), nil
+}
+
+// provideSources implements the sourceProvider interface. The values are
+// already resolved, so a literal ${ is escaped to keep it from being resolved
+// again.
+func (s Snapshot) provideSources() (provided, error) {
+	pv := provided{
+		m:      make(map[string]string, len(s.m)),
+		src:    make(map[string]string, len(s.m)),
+		secret: make(map[string]bool, len(s.secret)),
+	}
+
+	name := providerName(s)
+	for k, v := range s.m {
+		pv.m[k] = strings.Replace(v, "${", "$${", -1)
+		pv.src[k] = name
+		if s.secret[k] {
+			pv.secret[k] = true
+		}
+	}
+
+	return pv, nil
+}
+
+// Value returns the value of the given key and whether it was found.
+func (s Snapshot) Value(key string) (string, bool) {
+	v, found := s.m[key]
+	return v, found
+}
+
+// Source returns the source of the given key when the Snapshot was taken, see
+// Config.Source.
+func (s Snapshot) Source(key string) string {
+	return s.src[key]
+}
+
+// Masked returns a copy of the values in the Snapshot with the values that
+// held secrets masked.
+func (s Snapshot) Masked() map[string]string {
+	m := make(map[string]string, len(s.masked))
+	for k, v := range s.masked {
+		m[k] = v
+	}
+
+	return m
+}
+
+// Map returns a copy of the values in the Snapshot.
+func (s Snapshot) Map() map[string]string {
+	m := make(map[string]string, len(s.m))
+	for k, v := range s.m {
+		m[k] = v
+	}
+
+	return m
+}
+
+// Diff returns the keys that differ between the Snapshot and other, with
+// other treated as the newer configuration. Values that held secrets in their
+// Snapshot are masked.
+func (s Snapshot) Diff(other Snapshot) Diff {
+	d := Diff{
+		Added:   make(map[string]string),
+		Removed: make(map[string]string),
+		Changed: make(map[string]Change),
+	}
+
+	o, n := changes(s.m, other.m, nil)
+	for k := range o {
+		if _, found := n[k]; found {
+			d.Changed[k] = Change{Old: s.shown(k), New: other.shown(k)}
+			continue
+		}
+		d.Removed[k] = s.shown(k)
+	}
+	for k := range n {
+		if _, found := o[k]; !found {
+			d.Added[k] = other.shown(k)
+		}
+	}
+
+	return d
+}
+
+// shown returns the value of the key, masked if it held a secret.
+func (s Snapshot) shown(key string) string {
+	if s.secret[key] {
+		return s.masked[key]
+	}
+
+	return s.m[key]
+}
+
+// Change holds the old and new value of a key that was changed.
+type Change struct {
+	Old string
+	New string
+}
+
+// Diff holds the differences between two configurations. Added and Changed
+// hold the values of the newer configuration, Removed holds the values of the
+// older configuration.
+type Diff struct {
+	Added   map[string]string
+	Removed map[string]string
+	Changed map[string]Change
+}
+
+// Empty reports whether there are no differences.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the keys that differ between the Config and other, with other
+// treated as the newer configuration. Values that hold secrets are masked.
+func (c *Config) Diff(other *Config) Diff {
+	return c.Snapshot().Diff(other.Snapshot())
+}
@@ -1,60 +1,363 @@
 package cfg
 
 import (
-	"bufio"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 )
 
 // FileProvider describes a file based loader which loads the configuration
-// from a file listed.
+// from a file listed. The file uses the dotenv format:
+//
+//	# Comments start with #.
+//	export HOST=localhost        # The export prefix is optional.
+//	EMPTY=
+//	GREETING="Hello\tWorld\n"    # Double quotes support escapes.
+//	LITERAL='No ${EXPANSION} \n' # Single quotes are taken as is.
+//	CERT="-----BEGIN-----
+//	...
+//	-----END-----"
+//	URL=http://${HOST}:${PORT:-8080}/
+//
+// References to ${VAR} or $VAR in unquoted and double quoted values are
+// expanded from the keys defined earlier in the file and then from the process
+// environment. A default can be given with ${VAR:-default}. References that
+// can't be resolved are left in place for the Config to resolve and \$
+// produces a literal $. Provide returns single quoted and escaped references
+// as written, a Config created from the FileProvider keeps them unresolved.
+// Any line that can't be parsed is reported as an error with its line number.
+//
+// When Namespace is set, only keys with the {NAMESPACE_} prefix are loaded
+// and the keys are stored without the prefix and made uppercase, the same as
+// the EnvProvider.
 type FileProvider struct {
-	Filename string
+	Filename  string
+	Namespace string
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (fp FileProvider) String() string {
+	return "file:" + fp.Filename
 }
 
 // Provide implements the Provider interface.
 func (fp FileProvider) Provide() (map[string]string, error) {
-	var config = make(map[string]string)
+	config, err := fp.read()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range config {
+		config[k] = strings.Replace(v, "$${", "${", -1)
+	}
+
+	return config, nil
+}
+
+// provideSources implements the sourceProvider interface. The values keep
+// the literal ${ escaped as $${ so the Config doesn't resolve them.
+func (fp FileProvider) provideSources() (provided, error) {
+	config, err := fp.read()
+	if err != nil {
+		return provided{}, err
+	}
+
+	pv := provided{
+		m:   config,
+		src: make(map[string]string, len(config)),
+	}
+
+	for k := range config {
+		pv.src[k] = fp.String()
+	}
+
+	return pv, nil
+}
 
-	file, err := os.Open(fp.Filename)
+// read loads the keys in the Namespace from the file. A literal ${ is
+// returned as $${.
+func (fp FileProvider) read() (map[string]string, error) {
+	data, err := ioutil.ReadFile(fp.Filename)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	scanner.Split(bufio.ScanLines)
+	config, err := parseDotenv(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", fp.Filename, err)
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	if fp.Namespace == "" {
+		return config, nil
+	}
 
-		if len(line) < 3 {
-			// the line doesn't have enough data
-			continue
+	nsConfig := make(map[string]string)
+	for k, v := range config {
+		if key, ok := namespaceKey(k, fp.Namespace); ok {
+			nsConfig[key] = v
+		}
+	}
+
+	// Did we find any keys for this namespace?
+	if len(nsConfig) == 0 {
+		return nil, fmt.Errorf("Namespace %q was not found", fp.Namespace)
+	}
+
+	return nsConfig, nil
+}
+
+// parseDotenv parses the dotenv formatted data. Errors are prefixed with the
+// line number they occurred on.
+func parseDotenv(data string) (map[string]string, error) {
+	config := make(map[string]string)
+
+	lookup := func(key string) (string, bool) {
+		if v, found := config[key]; found {
+			return v, true
 		}
+		return os.LookupEnv(key)
+	}
+
+	lines := strings.Split(strings.Replace(data, "\r\n", "\n", -1), "\n")
 
-		if line[0] == '#' {
-			// the line starts with a comment character
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' {
 			continue
 		}
 
-		// find the first equals sign
-		index := strings.Index(line, "=")
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+			line = strings.TrimSpace(line[len("export"):])
+		}
+
+		idx := strings.IndexByte(line, '=')
+		if idx == -1 {
+			return nil, fmt.Errorf("%d: expected KEY=VALUE", n)
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		if !validKey(key) {
+			return nil, fmt.Errorf("%d: invalid key %q", n, key)
+		}
 
-		// if we couldn't find one
-		if index <= 0 {
-			// the line is invalid
+		raw := strings.TrimLeft(line[idx+1:], " \t")
+
+		// Unquoted values end at an inline comment.
+		if raw == "" || (raw[0] != '"' && raw[0] != '\'') {
+			if strings.HasPrefix(raw, "#") {
+				raw = ""
+			}
+			if idx := strings.Index(raw, " #"); idx != -1 {
+				raw = raw[:idx]
+			}
+			if idx := strings.Index(raw, "\t#"); idx != -1 {
+				raw = raw[:idx]
+			}
+
+			config[key] = expandDotenv(strings.TrimSpace(raw), false, lookup)
 			continue
 		}
 
-		if index == len(line)-1 {
-			// the line is invalid
+		// Quoted values may span multiple lines until the closing quote.
+		q := raw[0]
+		body := raw[1:]
+		for {
+			end := closingQuote(body, q)
+			if end != -1 {
+				if rest := strings.TrimSpace(body[end+1:]); rest != "" && rest[0] != '#' {
+					return nil, fmt.Errorf("%d: unexpected text after quoted value", i+1)
+				}
+				body = body[:end]
+				break
+			}
+
+			i++
+			if i == len(lines) {
+				return nil, fmt.Errorf("%d: unterminated quoted value", n)
+			}
+			body += "\n" + lines[i]
+		}
+
+		// Escape references in literal values so the Config doesn't resolve
+		// them either.
+		if q == '\'' {
+			config[key] = strings.Replace(body, "${", "$${", -1)
 			continue
 		}
 
-		// add the item to the config
-		config[line[:index]] = line[index+1:]
+		config[key] = expandDotenv(body, true, lookup)
 	}
 
 	return config, nil
 }
+
+// validKey reports whether the key only holds letters, digits, underscores,
+// dots and dashes and doesn't start with a digit.
+func validKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '_', r == '.', r == '-':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// closingQuote returns the index of the quote that closes the value, or -1 if
+// it's not found. Double quotes can be escaped with a backslash.
+func closingQuote(s string, q byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q == '"':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expandDotenv processes escape sequences and expands variable references in
+// the value. Only \$ is an escape sequence unless escapes is set, in which case
+// \n, \r, \t, \", \\ and \$ are supported.
+func expandDotenv(s string, escapes bool, lookup func(string) (string, bool)) string {
+	var buf strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			next := s[i+1]
+
+			switch {
+			case next == '$':
+
+				// Keep an escaped reference escaped so the Config doesn't
+				// resolve it either.
+				if i+2 < len(s) && s[i+2] == '{' {
+					buf.WriteByte('$')
+				}
+				buf.WriteByte('$')
+			case !escapes:
+				buf.WriteByte('\\')
+				continue
+			case next == 'n':
+				buf.WriteByte('\n')
+			case next == 'r':
+				buf.WriteByte('\r')
+			case next == 't':
+				buf.WriteByte('\t')
+			case next == '"', next == '\\':
+				buf.WriteByte(next)
+			default:
+				buf.WriteByte('\\')
+				buf.WriteByte(next)
+			}
+			i++
+
+		case s[i] == '$':
+			ref, name, def, ok := parseReference(s[i:])
+			if !ok {
+				buf.WriteByte('$')
+				continue
+			}
+
+			v, found := lookup(name)
+			switch {
+			case found && v != "":
+				buf.WriteString(v)
+			case def != nil:
+				buf.WriteString(*def)
+			case found:
+			default:
+				buf.WriteString(ref)
+			}
+			i += len(ref) - 1
+
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+
+	return buf.String()
+}
+
+// parseReference parses a ${NAME}, ${NAME:-default} or $NAME reference at the
+// start of s. It returns the full text of the reference, the name and the
+// default if one was given.
+func parseReference(s string) (string, string, *string, bool) {
+	if len(s) < 2 {
+		return "", "", nil, false
+	}
+
+	if s[1] == '{' {
+		end := strings.IndexByte(s, '}')
+		if end == -1 {
+			return "", "", nil, false
+		}
+
+		name := s[2:end]
+
+		var def *string
+		if idx := strings.Index(name, ":-"); idx != -1 {
+			d := name[idx+2:]
+			def = &d
+			name = name[:idx]
+		}
+
+		if !validName(name) {
+			return "", "", nil, false
+		}
+
+		return s[:end+1], name, def, true
+	}
+
+	end := 1
+	for end < len(s) && isNameByte(s[end], end == 1) {
+		end++
+	}
+
+	if end == 1 {
+		return "", "", nil, false
+	}
+
+	return s[:end], s[1:end], nil, true
+}
+
+// validName reports whether the name can be referenced.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i], i == 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNameByte reports whether the byte can be part of a referenced name.
+func isNameByte(b byte, first bool) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b == '_':
+		return true
+	case b >= '0' && b <= '9':
+		return !first
+	}
+
+	return false
+}
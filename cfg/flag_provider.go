@@ -0,0 +1,177 @@
+package cfg
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// FlagProvider provides configuration from command line flags. Flag names
+// are the lowercase form of the keys with underscores replaced by dashes, so
+// the flag --db-host provides the key DB_HOST, matching the keys produced by
+// the EnvProvider. Only flags given on the command line are provided, which
+// allows the FlagProvider to be listed last in a MultiProvider to override the
+// other Providers.
+//
+// When a Schema is given, a flag is registered on the FlagSet for every key in
+// the Schema using its Description as the usage, and values are checked
+// against the key's Type as they are parsed. FlagSet defaults to a new FlagSet
+// that is parsed on every read and doesn't touch flag.CommandLine, so the
+// application keeps its own flags. The FlagSet is parsed from Args, which
+// defaults to os.Args[1:], and the parse error is returned for unknown flags or
+// invalid values. An error is returned if the FlagSet was already parsed before
+// the flags were registered, since the flags would never be set:
+//
+//	schema := cfg.Schema{{Name: "DB_HOST", Type: cfg.TypeString, Description: "Database host."}}
+//
+//	c, err := cfg.New(cfg.MultiProvider{
+//		Providers: []cfg.Provider{schema, cfg.EnvProvider{Namespace: "APP"}, cfg.FlagProvider{Schema: schema}},
+//	})
+//
+// Without a Schema no flags are registered and Args are scanned for any flag in
+// the form --name=value or --name value. A --name followed by another flag or
+// by nothing provides "true", use --name=true when it is followed by an
+// argument that isn't its value. Scanning stops at the first "--".
+type FlagProvider struct {
+	Schema  Schema
+	FlagSet *flag.FlagSet
+	Args    []string
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (fp FlagProvider) String() string {
+	return "flags"
+}
+
+// Provide implements the Provider interface.
+func (fp FlagProvider) Provide() (map[string]string, error) {
+	args := fp.Args
+	if args == nil && len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
+
+	if len(fp.Schema) == 0 {
+		return scanFlags(args), nil
+	}
+
+	fs := fp.FlagSet
+	if fs == nil {
+		fs = flag.NewFlagSet("flags", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+	}
+
+	keys := make(map[string]bool, len(fp.Schema))
+	var registered bool
+	for _, k := range fp.Schema {
+		keys[k.Name] = true
+
+		// Providers can be read more than once, so only register new flags.
+		name := flagName(k.Name)
+		if fs.Lookup(name) == nil {
+			fs.Var(&flagValue{key: k}, name, k.Description)
+			registered = true
+		}
+	}
+
+	if registered && fs.Parsed() {
+		return nil, errors.New("flags were parsed before the Schema flags were registered")
+	}
+
+	if !fs.Parsed() {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+	}
+
+	config := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if fv, ok := f.Value.(*flagValue); ok && keys[fv.key.Name] {
+			config[fv.key.Name] = fv.value
+		}
+	})
+
+	return config, nil
+}
+
+// flagValue implements the flag.Value interface for a key in a Schema.
+type flagValue struct {
+	key   Key
+	value string
+}
+
+// String implements the flag.Value interface.
+func (fv *flagValue) String() string {
+	if fv == nil {
+		return ""
+	}
+
+	return fv.value
+}
+
+// Set implements the flag.Value interface.
+func (fv *flagValue) Set(value string) error {
+	if err := fv.key.Type.check(value); err != nil {
+		return fmt.Errorf("value %q is not a valid %s", value, fv.key.Type)
+	}
+
+	fv.value = value
+	return nil
+}
+
+// IsBoolFlag allows bool keys to be given as a flag without a value.
+func (fv *flagValue) IsBoolFlag() bool {
+	return fv.key.Type == TypeBool
+}
+
+// scanFlags returns a key for every --name=value, --name value or --name flag
+// in args.
+func scanFlags(args []string) map[string]string {
+	config := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		arg = strings.TrimLeft(arg, "-")
+		if arg == "" {
+			continue
+		}
+
+		name, value := arg, "true"
+		switch idx := strings.Index(arg, "="); {
+		case idx != -1:
+			name, value = arg[:idx], arg[idx+1:]
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"):
+			value = args[i+1]
+			i++
+		}
+
+		if name == "" {
+			continue
+		}
+
+		config[keyName(name)] = value
+	}
+
+	return config
+}
+
+// flagName converts a key to the name of its flag.
+func flagName(key string) string {
+	return strings.ToLower(strings.Replace(key, "_", "-", -1))
+}
+
+// keyName converts the name of a flag to its key.
+func keyName(name string) string {
+	return strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
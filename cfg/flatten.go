@@ -0,0 +1,105 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flattener converts a nested document into the flat key/value map used by
+// a Config.
+type flattener struct {
+	sep   string
+	upper bool
+	m     map[string]string
+}
+
+// newFlattener returns a flattener joining keys with sep, defaulting to ".",
+// and optionally making all keys uppercase.
+func newFlattener(sep string, upper bool) *flattener {
+	if sep == "" {
+		sep = "."
+	}
+
+	return &flattener{
+		sep:   sep,
+		upper: upper,
+		m:     make(map[string]string),
+	}
+}
+
+// key joins the prefix and name into a key.
+func (f *flattener) key(prefix, name string) string {
+	if f.upper {
+		name = strings.ToUpper(name)
+	}
+
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + f.sep + name
+}
+
+// add flattens the value into the map under the given key. Nested maps extend
+// the key with their own keys. Arrays of scalars are stored as a comma
+// separated list that can be read with StringSlice, other arrays extend the
+// key with the index of each element.
+func (f *flattener) add(key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, sv := range v {
+			f.add(f.key(key, k), sv)
+		}
+
+	case []interface{}:
+		strs, ok := scalars(v)
+		if ok {
+			f.m[key] = formatStringSlice(strs)
+			return
+		}
+
+		for i, sv := range v {
+			f.add(f.key(key, strconv.Itoa(i)), sv)
+		}
+
+	default:
+		f.m[key] = scalar(v)
+	}
+}
+
+// scalars converts the array to a slice of strings if every element is a
+// scalar.
+func scalars(vs []interface{}) ([]string, bool) {
+	strs := make([]string, len(vs))
+
+	for i, v := range vs {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, false
+		}
+
+		strs[i] = scalar(v)
+	}
+
+	return strs, true
+}
+
+// scalar converts a scalar document value to a string.
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	return fmt.Sprint(v)
+}
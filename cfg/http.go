@@ -0,0 +1,199 @@
+package cfg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxPatchBytes limits the size of a PATCH request body.
+const maxPatchBytes = 1 << 20
+
+// Endpoint serves the configuration over HTTP for operators. A GET request
+// returns every key as a JSON object holding its masked value and source:
+//
+//	{"DB_HOST": {"value": "localhost", "source": "env:APP"}}
+//
+// PATCH requests are rejected unless Authorize is set along with a Schema or
+// a list of Writable keys. A PATCH request is authorized by calling Authorize
+// and its body must be a JSON object of keys and their new values. Only the
+// Writable keys can be set or, when none are listed, the keys in the Schema,
+// and each value in the Schema must pass validation. If any key is invalid
+// nothing is set and the problems are returned as a 400. The values are set
+// together, as if by the Set functions, so subscribers registered with
+// OnChange are notified once of every change. Values are stored as given, a
+// ${KEY} in a value is not resolved, so a PATCH can't read other keys or the
+// environment.
+//
+// Handle matches the web.Handler signature so it can be routed by a web.App:
+//
+//	ep := cfg.Endpoint{Config: c, Schema: schema, Authorize: auth}
+//	app.Handle("GET", "/config", ep.Handle)
+//	app.Handle("PATCH", "/config", ep.Handle)
+//
+// Endpoint also implements the http.Handler interface. When Config is nil the
+// default Config is used.
+type Endpoint struct {
+	Config    *Config
+	Schema    Schema
+	Writable  []string
+	Authorize func(r *http.Request) error
+}
+
+// entry is the JSON representation of a key served by an Endpoint.
+type entry struct {
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+}
+
+// jsonError is the JSON representation of a failed request, matching the
+// errors returned by the web package.
+type jsonError struct {
+	Error  string       `json:"error"`
+	Fields InvalidError `json:"fields,omitempty"`
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (e Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.Handle(r.Context(), w, r, nil)
+}
+
+// Handle serves a GET or PATCH request. It only returns an error when the
+// response could not be written.
+func (e Endpoint) Handle(ctx context.Context, w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	conf := e.Config
+	if conf == nil {
+		conf = &c
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return respond(w, entries(conf), http.StatusOK)
+
+	case http.MethodPatch:
+		if !e.patchable() {
+			break
+		}
+
+		if err := e.Authorize(r); err != nil {
+			return respond(w, jsonError{Error: err.Error()}, http.StatusUnauthorized)
+		}
+
+		var body map[string]interface{}
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPatchBytes))
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil {
+			return respond(w, jsonError{Error: fmt.Sprintf("invalid body : %v", err)}, http.StatusBadRequest)
+		}
+
+		values, err := e.validate(body)
+		if err != nil {
+			return respond(w, jsonError{Error: "key validation failure", Fields: err}, http.StatusBadRequest)
+		}
+
+		conf.setValues(values)
+
+		return respond(w, entries(conf), http.StatusOK)
+	}
+
+	allow := []string{http.MethodGet}
+	if e.patchable() {
+		allow = append(allow, http.MethodPatch)
+	}
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+
+	return respond(w, jsonError{Error: "method not allowed"}, http.StatusMethodNotAllowed)
+}
+
+// patchable reports whether PATCH requests are accepted.
+func (e Endpoint) patchable() bool {
+	return e.Authorize != nil && (e.Schema != nil || e.Writable != nil)
+}
+
+// validate converts the values of a PATCH body to strings, checks they can be
+// written and validates them against the Schema. The values are escaped so
+// they are stored literally.
+func (e Endpoint) validate(body map[string]interface{}) (map[string]string, InvalidError) {
+	keys := make(map[string]Key, len(e.Schema))
+	for _, k := range e.Schema {
+		keys[k.Name] = k
+	}
+
+	writable := make(map[string]bool)
+	for _, name := range e.Writable {
+		writable[name] = true
+	}
+
+	names := make([]string, 0, len(body))
+	for name := range body {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]string, len(body))
+
+	var inv InvalidError
+	for _, name := range names {
+		switch body[name].(type) {
+		case map[string]interface{}, []interface{}, nil:
+			inv = append(inv, Invalid{Key: name, Err: "value must be a string, number or bool"})
+			continue
+		}
+
+		value := scalar(body[name])
+
+		k, found := keys[name]
+		switch {
+		case e.Writable != nil && !writable[name]:
+			inv = append(inv, Invalid{Key: name, Err: "key is not writable"})
+			continue
+		case e.Writable == nil && !found:
+			inv = append(inv, Invalid{Key: name, Err: "key is not in the schema"})
+			continue
+		}
+
+		if found {
+			if err := k.validate(value); err != nil {
+				inv = append(inv, Invalid{Key: name, Err: err.Error()})
+				continue
+			}
+		}
+
+		values[name] = strings.Replace(value, "${", "$${", -1)
+	}
+
+	if inv != nil {
+		return nil, inv
+	}
+
+	return values, nil
+}
+
+// entries returns the masked value and source of every key in the Config.
+func entries(c *Config) map[string]entry {
+	s := c.Snapshot()
+
+	m := make(map[string]entry)
+	for k, v := range c.Masked() {
+		m[k] = entry{Value: v, Source: s.Source(k)}
+	}
+
+	return m
+}
+
+// respond writes v as JSON with the given status code.
+func respond(w http.ResponseWriter, v interface{}, code int) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,226 @@
+package cfg
+
+import (
+	"os"
+	"strings"
+)
+
+// SetEnvFallback controls whether ${KEY} references to keys that are not in
+// the Config are looked up in the process environment. The values are resolved
+// again and any changes are delivered to subscribers. It will return an error
+// if the values contain a reference cycle, the keys that are part of the cycle
+// keep their unresolved value.
+func (c *Config) SetEnvFallback(enabled bool) error {
+	if c.parent != nil {
+		return c.parent.SetEnvFallback(enabled)
+	}
+
+	c.mu.Lock()
+	c.env = enabled
+
+	m, err := resolve(c.raw, c.env, c.dec)
+	old := c.m
+	c.m = m
+	subs := c.subs
+	c.mu.Unlock()
+
+	notify(subs, old, m)
+
+	return err
+}
+
+// resolver expands ${KEY} references between the values of a Config.
+type resolver struct {
+	raw    map[string]string
+	env    bool
+	m      map[string]string
+	state  map[string]int
+	stack  []string
+	cyclic map[string]bool
+	inv    InvalidError
+}
+
+// Set of states a key can be in while it is being resolved.
+const (
+	unresolved = iota
+	resolving
+	resolved
+)
+
+// interpolate returns a copy of the raw values with every ${KEY} reference
+// replaced by the resolved value of KEY. A default can be given with
+// ${KEY:-default} and $${ produces a literal ${. When env is set, references
+// to keys that are not found are looked up in the process environment.
+// References that can't be resolved are left in place. Keys that are part of
+// a reference cycle keep their raw value and are reported in the returned
+// InvalidError.
+func interpolate(raw map[string]string, env bool) (map[string]string, error) {
+	r := resolver{
+		raw:    raw,
+		env:    env,
+		m:      make(map[string]string, len(raw)),
+		state:  make(map[string]int, len(raw)),
+		cyclic: make(map[string]bool),
+	}
+
+	for k := range raw {
+		r.value(k)
+	}
+
+	if r.inv != nil {
+		return r.m, r.inv
+	}
+
+	return r.m, nil
+}
+
+// value returns the resolved value of the key.
+func (r *resolver) value(key string) (string, bool) {
+	raw, found := r.raw[key]
+	if !found {
+		if r.env {
+			return os.LookupEnv(key)
+		}
+		return "", false
+	}
+
+	switch r.state[key] {
+	case resolved:
+		return r.m[key], true
+
+	case resolving:
+		var idx int
+		for i, k := range r.stack {
+			if k == key {
+				idx = i
+				break
+			}
+		}
+
+		for _, k := range r.stack[idx:] {
+			r.cyclic[k] = true
+		}
+
+		cycle := strings.Join(append(r.stack[idx:len(r.stack):len(r.stack)], key), " -> ")
+		r.inv = append(r.inv, Invalid{Key: key, Err: "reference cycle " + cycle})
+
+		return "", false
+	}
+
+	r.state[key] = resolving
+	r.stack = append(r.stack, key)
+
+	v := r.expand(raw)
+
+	r.stack = r.stack[:len(r.stack)-1]
+	r.state[key] = resolved
+
+	// A key found to be part of a cycle keeps its raw value.
+	if r.cyclic[key] {
+		v = raw
+	}
+	r.m[key] = v
+
+	return v, true
+}
+
+// expand replaces the references in s with their resolved values.
+func (r *resolver) expand(s string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var buf strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		// An escaped reference is written as a literal ${.
+		if strings.HasPrefix(s[i:], "$${") {
+			buf.WriteString("${")
+			i += 2
+			continue
+		}
+
+		if !strings.HasPrefix(s[i:], "${") {
+			buf.WriteByte('$')
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			buf.WriteString(s[i:])
+			break
+		}
+
+		ref := s[i : i+end+1]
+		name := ref[2 : len(ref)-1]
+
+		var def *string
+		if idx := strings.Index(name, ":-"); idx != -1 {
+			d := name[idx+2:]
+			def = &d
+			name = name[:idx]
+		}
+
+		if !validKey(name) {
+			buf.WriteString(ref)
+			i += end
+			continue
+		}
+
+		v, found := r.value(name)
+
+		switch {
+		case found && v != "":
+			buf.WriteString(v)
+		case def != nil:
+			buf.WriteString(*def)
+		case found:
+		default:
+			buf.WriteString(ref)
+		}
+
+		i += end
+	}
+
+	return buf.String()
+}
+
+// references returns the names of the keys referenced by s, in the same way
+// they are found when s is expanded.
+func references(s string) []string {
+	var names []string
+
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "$${") {
+			i += 2
+			continue
+		}
+
+		if !strings.HasPrefix(s[i:], "${") {
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			break
+		}
+
+		name := s[i+2 : i+end]
+		if idx := strings.Index(name, ":-"); idx != -1 {
+			name = name[:idx]
+		}
+
+		if validKey(name) {
+			names = append(names, name)
+		}
+
+		i += end
+	}
+
+	return names
+}
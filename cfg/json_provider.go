@@ -0,0 +1,52 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// JSONProvider describes a file based loader which loads the configuration
+// from a JSON document. Nested objects are flattened into keys joined with
+// Separator, which defaults to ".", and are made uppercase when Uppercase is
+// set. Given the document:
+//
+//	{"db": {"host": "localhost", "pool": {"max": 10}}, "tags": ["a", "b"]}
+//
+// the keys are db.host, db.pool.max and tags, or DB_HOST, DB_POOL_MAX and TAGS
+// with a Separator of "_" and Uppercase set. Arrays of scalars are stored as
+// a comma separated list that can be read with StringSlice, other arrays are
+// flattened using the index of each element as a key.
+type JSONProvider struct {
+	Filename  string
+	Separator string
+	Uppercase bool
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (jp JSONProvider) String() string {
+	return "json:" + jp.Filename
+}
+
+// Provide implements the Provider interface.
+func (jp JSONProvider) Provide() (map[string]string, error) {
+	file, err := os.Open(jp.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dec := json.NewDecoder(file)
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s : %v", jp.Filename, err)
+	}
+
+	f := newFlattener(jp.Separator, jp.Uppercase)
+	f.add("", doc)
+
+	return f.m, nil
+}
@@ -6,6 +6,12 @@ type MapProvider struct {
 	Map map[string]string
 }
 
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (mp MapProvider) String() string {
+	return "map"
+}
+
 // Provide implements the Provider interface.
 func (mp MapProvider) Provide() (map[string]string, error) {
 	return mp.Map, nil
@@ -0,0 +1,106 @@
+package cfg
+
+import "fmt"
+
+// MultiProvider provides configuration merged from a list of Providers. The
+// Providers are read in order and a key supplied by a later Provider overrides
+// the same key supplied by an earlier one, so defaults should be listed first:
+//
+//	cfg.Init(cfg.MultiProvider{
+//		Providers: []cfg.Provider{
+//			cfg.FileProvider{Filename: "defaults.env"},
+//			cfg.EnvProvider{Namespace: "APP"},
+//		},
+//	})
+//
+// A Config created from a MultiProvider records which Provider supplied each
+// key, see Config.Source.
+type MultiProvider struct {
+	Providers []Provider
+}
+
+// Provide implements the Provider interface.
+func (mp MultiProvider) Provide() (map[string]string, error) {
+	pv, err := mp.provideSources()
+	return pv.m, err
+}
+
+// provideSources implements the sourceProvider interface.
+func (mp MultiProvider) provideSources() (provided, error) {
+	pv := provided{
+		m:      make(map[string]string),
+		src:    make(map[string]string),
+		secret: make(map[string]bool),
+	}
+
+	for _, p := range mp.Providers {
+		ppv, err := provide(p)
+		if err != nil {
+			return provided{}, fmt.Errorf("%s : %v", providerName(p), err)
+		}
+
+		for k, v := range ppv.m {
+			pv.m[k] = v
+			pv.src[k] = ppv.src[k]
+			if ppv.secret[k] {
+				pv.secret[k] = true
+			} else {
+				delete(pv.secret, k)
+			}
+		}
+	}
+
+	return pv, nil
+}
+
+// provided holds the values read from a Provider, the source of each key and
+// the keys the Provider knows to hold secrets.
+type provided struct {
+	m      map[string]string
+	src    map[string]string
+	secret map[string]bool
+}
+
+// sourceProvider is implemented by Providers that can report which Provider
+// supplied each key and which keys hold secrets. The values may hold $${ for
+// a literal ${ that the Config must not resolve.
+type sourceProvider interface {
+	provideSources() (provided, error)
+}
+
+// provide reads the configuration from the Provider along with the source of
+// each key.
+func provide(p Provider) (provided, error) {
+	if sp, ok := p.(sourceProvider); ok {
+		return sp.provideSources()
+	}
+
+	m, err := p.Provide()
+	if err != nil {
+		return provided{}, err
+	}
+
+	// Providers may hand back a map they keep using, so take a copy.
+	pv := provided{
+		m:   make(map[string]string, len(m)),
+		src: make(map[string]string, len(m)),
+	}
+
+	name := providerName(p)
+	for k, v := range m {
+		pv.m[k] = v
+		pv.src[k] = name
+	}
+
+	return pv, nil
+}
+
+// providerName returns the name used to identify the Provider as a source.
+// Providers can choose their name by implementing fmt.Stringer.
+func providerName(p Provider) string {
+	if s, ok := p.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", p)
+}
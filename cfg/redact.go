@@ -0,0 +1,168 @@
+package cfg
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaskFunc returns the masked form of a secret value.
+type MaskFunc func(value string) string
+
+// MaskLength masks the value showing only its length.
+func MaskLength(value string) string {
+	return fmt.Sprintf("[REDACTED len=%d]", len(value))
+}
+
+// MaskHash masks the value showing only a prefix of its SHA-256 hash, which
+// allows values to be compared across environments without revealing them.
+func MaskHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("[REDACTED sha256=%x]", sum[:4])
+}
+
+// Redactor is a policy that decides which keys hold secrets and how their
+// values are masked when the Config is logged or exported. A key is a secret
+// if it contains any of the Substrings, ignoring case, matches any of the
+// Patterns or is listed in Keys. Mask defaults to MaskLength.
+type Redactor struct {
+	Substrings []string
+	Patterns   []*regexp.Regexp
+	Keys       []string
+	Mask       MaskFunc
+}
+
+// DefaultRedactor is the policy used by a Config until SetRedactor is called.
+var DefaultRedactor = Redactor{
+	Substrings: []string{"PASS", "SECRET", "TOKEN", "API_KEY", "PRIVATE_KEY", "CREDENTIAL"},
+	Mask:       MaskLength,
+}
+
+// IsSecret reports whether the key holds a secret according to the policy.
+func (r Redactor) IsSecret(key string) bool {
+	for _, k := range r.Keys {
+		if k == key {
+			return true
+		}
+	}
+
+	ukey := strings.ToUpper(key)
+	for _, s := range r.Substrings {
+		if strings.Contains(ukey, strings.ToUpper(s)) {
+			return true
+		}
+	}
+
+	for _, p := range r.Patterns {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns the value masked if the key holds a secret, else the value
+// is returned unchanged.
+func (r Redactor) Redact(key, value string) string {
+	if !r.IsSecret(key) {
+		return value
+	}
+
+	return r.mask(value)
+}
+
+// mask masks the value using the policy's MaskFunc.
+func (r Redactor) mask(value string) string {
+	if r.Mask == nil {
+		return MaskLength(value)
+	}
+
+	return r.Mask(value)
+}
+
+// SetRedactor replaces the policy used to mask secret values when the Config
+// is logged or exported. Views created with Sub share the policy of their
+// Config.
+func (c *Config) SetRedactor(r Redactor) {
+	if c.parent != nil {
+		c.parent.SetRedactor(r)
+		return
+	}
+
+	c.mu.Lock()
+	{
+		c.redact = &r
+	}
+	c.mu.Unlock()
+}
+
+// Masked returns a copy of the configuration with every secret value masked
+// according to the Config's Redactor, along with any values that were
+// encrypted and the values that reference either.
+func (c *Config) Masked() map[string]string {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	red := r.redactor()
+
+	m := make(map[string]string)
+	for _, k := range r.sortedKeys(prefix) {
+		m[strings.TrimPrefix(k, prefix)] = r.redactValue(red, k)
+	}
+
+	return m
+}
+
+// redactValue returns the value of the key masked if it holds a secret, see
+// tainted. The caller must hold the lock.
+func (c *Config) redactValue(r Redactor, key string) string {
+	value := c.m[key]
+
+	if c.tainted(r, key, make(map[string]bool)) {
+		return r.mask(value)
+	}
+
+	return value
+}
+
+// tainted reports whether the value of the key holds a secret. It does if the
+// Redactor reports the key as a secret, the value was encrypted, the Provider
+// reported it as a secret or the value references a key that holds a secret,
+// including variables from the environment when the fallback is enabled. The
+// caller must hold the lock.
+func (c *Config) tainted(r Redactor, key string, seen map[string]bool) bool {
+	if seen[key] {
+		return false
+	}
+	seen[key] = true
+
+	raw, found := c.raw[key]
+	if !found {
+		return c.env && r.IsSecret(key)
+	}
+
+	if r.IsSecret(key) || c.secret[key] || strings.HasPrefix(raw, EncryptedPrefix) {
+		return true
+	}
+
+	for _, name := range references(raw) {
+		if c.tainted(r, name, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactor returns the Redactor in use. The caller must hold the lock.
+func (c *Config) redactor() Redactor {
+	if c.redact == nil {
+		return DefaultRedactor
+	}
+
+	return *c.redact
+}
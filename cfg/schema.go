@@ -0,0 +1,296 @@
+package cfg
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Type identifies the type of a configuration value declared in a Schema.
+type Type int
+
+// Set of types that can be declared in a Schema.
+const (
+	TypeString Type = iota
+	TypeInt
+	TypeInt64
+	TypeUint
+	TypeFloat64
+	TypeBool
+	TypeTime
+	TypeURL
+	TypeDuration
+	TypeByteSize
+	TypeStringSlice
+	TypeIntSlice
+	TypeDurationSlice
+	TypeStringMap
+)
+
+// typeNames provides the display name of each Type.
+var typeNames = map[Type]string{
+	TypeString:        "string",
+	TypeInt:           "int",
+	TypeInt64:         "int64",
+	TypeUint:          "uint",
+	TypeFloat64:       "float64",
+	TypeBool:          "bool",
+	TypeTime:          "time",
+	TypeURL:           "url",
+	TypeDuration:      "duration",
+	TypeByteSize:      "bytesize",
+	TypeStringSlice:   "[]string",
+	TypeIntSlice:      "[]int",
+	TypeDurationSlice: "[]duration",
+	TypeStringMap:     "map",
+}
+
+// String implements the fmt.Stringer interface.
+func (t Type) String() string {
+	if name, found := typeNames[t]; found {
+		return name
+	}
+
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// check validates the value can be converted to the Type.
+func (t Type) check(value string) error {
+	var err error
+
+	switch t {
+	case TypeInt:
+		_, err = strconv.Atoi(value)
+	case TypeInt64:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TypeUint:
+		_, err = parseUint(value)
+	case TypeFloat64:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, err = parseBool(value)
+	case TypeTime:
+		_, err = parseTime(value)
+	case TypeURL:
+		_, err = url.Parse(value)
+	case TypeDuration:
+		_, err = time.ParseDuration(value)
+	case TypeByteSize:
+		_, err = parseByteSize(value)
+	case TypeStringSlice:
+		_, err = parseStringSlice(value)
+	case TypeIntSlice:
+		_, err = parseIntSlice(value)
+	case TypeDurationSlice:
+		_, err = parseDurationSlice(value)
+	case TypeStringMap:
+		_, err = parseStringMap(value)
+	}
+
+	return err
+}
+
+// number converts the value to a float64 so it can be compared against a
+// range. It returns false if the Type has no ordering.
+func (t Type) number(value string) (float64, bool, error) {
+	switch t {
+	case TypeInt, TypeInt64:
+		iv, err := strconv.ParseInt(value, 10, 64)
+		return float64(iv), true, err
+	case TypeUint:
+		uv, err := strconv.ParseUint(value, 10, 64)
+		return float64(uv), true, err
+	case TypeFloat64:
+		fv, err := strconv.ParseFloat(value, 64)
+		return fv, true, err
+	case TypeDuration:
+		d, err := time.ParseDuration(value)
+		return float64(d), true, err
+	case TypeByteSize:
+		bs, err := parseByteSize(value)
+		return float64(bs), true, err
+	}
+
+	return 0, false, nil
+}
+
+// Key declares a single configuration key in a Schema. Min and Max are
+// written in the same format as the value and only apply to numeric, duration
+// and byte size types. When Enum is not empty the value must be one of its
+// elements.
+type Key struct {
+	Name        string
+	Type        Type
+	Default     string
+	Required    bool
+	Min         string
+	Max         string
+	Enum        []string
+	Description string
+}
+
+// validate checks the value against the declaration of the Key.
+func (k Key) validate(value string) error {
+	if err := k.Type.check(value); err != nil {
+		return fmt.Errorf("value %q is not a valid %s", value, k.Type)
+	}
+
+	if len(k.Enum) > 0 {
+		var found bool
+		for _, e := range k.Enum {
+			if e == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("value %q is not one of %s", value, strings.Join(k.Enum, ", "))
+		}
+	}
+
+	if k.Min == "" && k.Max == "" {
+		return nil
+	}
+
+	n, ordered, _ := k.Type.number(value)
+	if !ordered {
+		return fmt.Errorf("type %s does not support a range", k.Type)
+	}
+
+	if k.Min != "" {
+		min, _, err := k.Type.number(k.Min)
+		if err != nil {
+			return fmt.Errorf("invalid minimum %q", k.Min)
+		}
+
+		if n < min {
+			return fmt.Errorf("value %s is less than the minimum %s", value, k.Min)
+		}
+	}
+
+	if k.Max != "" {
+		max, _, err := k.Type.number(k.Max)
+		if err != nil {
+			return fmt.Errorf("invalid maximum %q", k.Max)
+		}
+
+		if n > max {
+			return fmt.Errorf("value %s is greater than the maximum %s", value, k.Max)
+		}
+	}
+
+	return nil
+}
+
+// allowed describes the values the Key accepts for display.
+func (k Key) allowed() string {
+	if len(k.Enum) > 0 {
+		return strings.Join(k.Enum, "|")
+	}
+
+	if k.Min != "" || k.Max != "" {
+		return k.Min + ".." + k.Max
+	}
+
+	return "-"
+}
+
+// Schema declares the set of keys an application uses. A Schema is also a
+// Provider of the declared defaults, so it can be listed first in a
+// MultiProvider to have the defaults overridden by other Providers:
+//
+//	schema := cfg.Schema{
+//		{Name: "PORT", Type: cfg.TypeInt, Default: "8080", Min: "1", Max: "65535"},
+//		{Name: "MODE", Type: cfg.TypeString, Required: true, Enum: []string{"dev", "prod"}},
+//	}
+//
+//	c, err := cfg.New(cfg.MultiProvider{
+//		Providers: []cfg.Provider{schema, cfg.EnvProvider{Namespace: "APP"}},
+//	})
+//	if err := c.Validate(schema); err != nil {
+//		fmt.Print(schema.Usage())
+//	}
+type Schema []Key
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (s Schema) String() string {
+	return "schema"
+}
+
+// Provide implements the Provider interface and returns the keys that declare
+// a default.
+func (s Schema) Provide() (map[string]string, error) {
+	config := make(map[string]string)
+
+	for _, k := range s {
+		if k.Default != "" {
+			config[k.Name] = k.Default
+		}
+	}
+
+	return config, nil
+}
+
+// Usage returns a table describing every key in the Schema.
+func (s Schema) Usage() string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTYPE\tDEFAULT\tALLOWED\tDESCRIPTION")
+
+	for _, k := range s {
+		def := k.Default
+		switch {
+		case def != "":
+		case k.Required:
+			def = "(required)"
+		default:
+			def = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.Name, k.Type, def, k.allowed(), k.Description)
+	}
+
+	w.Flush()
+
+	return buf.String()
+}
+
+// Validate checks the Config against the Schema. Required keys must be found
+// and every key that is found must convert to its declared Type and fall
+// within its declared range or enum. Every violation is reported in the
+// returned InvalidError.
+func (c *Config) Validate(s Schema) error {
+	r, prefix := c.view()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var inv InvalidError
+
+	for _, k := range s {
+		value, found := r.m[prefix+k.Name]
+		if !found {
+			if k.Required {
+				inv = append(inv, Invalid{Key: k.Name, Err: "required key not found"})
+			}
+			continue
+		}
+
+		if err := k.validate(value); err != nil {
+			inv = append(inv, Invalid{Key: k.Name, Err: err.Error()})
+		}
+	}
+
+	if inv != nil {
+		return inv
+	}
+
+	return nil
+}
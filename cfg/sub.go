@@ -0,0 +1,51 @@
+package cfg
+
+import "strings"
+
+// Sub returns a view of the keys of the Config that start with prefix, with
+// the prefix removed. The view shares its values, lock and subscribers with
+// the Config, so changes made through either one are seen by the other:
+//
+//	db := cfg.Sub("DB_")
+//	host := db.MustString("HOST") // The value of DB_HOST.
+//	db.SetInt("PORT", 5432)       // Sets DB_PORT.
+//
+// Keys set through the view are added to the Config with the prefix. The
+// Redactor, Decrypter and environment fallback of the Config are used by the
+// view and setting them on the view sets them on the Config. Sub can be called
+// on a view to narrow it further.
+func (c *Config) Sub(prefix string) *Config {
+	if c.parent != nil {
+		return c.parent.Sub(c.prefix + prefix)
+	}
+
+	return &Config{parent: c, prefix: prefix}
+}
+
+// prefixKeys returns the keys with the prefix added. A nil list of keys
+// subscribes to every key so it is returned unchanged.
+func prefixKeys(keys []string, prefix string) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pk := make([]string, len(keys))
+	for i, k := range keys {
+		pk[i] = prefix + k
+	}
+
+	return pk
+}
+
+// trimKeys returns the keys of m that start with prefix, with the prefix
+// removed.
+func trimKeys(m map[string]string, prefix string) map[string]string {
+	tm := make(map[string]string)
+	for k, v := range m {
+		if strings.HasPrefix(k, prefix) {
+			tm[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+
+	return tm
+}
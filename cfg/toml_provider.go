@@ -0,0 +1,321 @@
+package cfg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TOMLProvider describes a file based loader which loads the configuration
+// from a TOML or INI style document. It supports the subset of TOML that is
+// commonly used for configuration files:
+//
+//	# Comments start with # or ;
+//	name = "kit"
+//	debug = true
+//
+//	[db]
+//	host = 'localhost'
+//	pool.max = 10
+//	hosts = ["a.local", "b.local"]
+//
+// Tables and dotted keys are flattened into keys joined with Separator, which
+// defaults to ".", and are made uppercase when Uppercase is set. Values may be
+// basic "strings" with escapes, 'literal strings', single line arrays or bare
+// values such as numbers, booleans and unquoted INI strings. Arrays are stored
+// as a comma separated list that can be read with StringSlice. Keys may be
+// quoted to hold dots, such as "example.com" = 1, and defining a table or key
+// twice is an error. Arrays of tables and multi-line strings are not
+// supported.
+type TOMLProvider struct {
+	Filename  string
+	Separator string
+	Uppercase bool
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (tp TOMLProvider) String() string {
+	return "toml:" + tp.Filename
+}
+
+// Provide implements the Provider interface.
+func (tp TOMLProvider) Provide() (map[string]string, error) {
+	file, err := os.Open(tp.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := parseTOML(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", tp.Filename, err)
+	}
+
+	f := newFlattener(tp.Separator, tp.Uppercase)
+	f.add("", doc)
+
+	return f.m, nil
+}
+
+// parseTOML reads the document into nested maps. Errors are prefixed with
+// the line number they occurred on.
+func parseTOML(r io.Reader) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	table := doc
+	tables := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+
+	var n int
+	for scanner.Scan() {
+		n++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		// Process a table header.
+		if line[0] == '[' {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("%d: arrays of tables are not supported", n)
+			}
+
+			parts, rest, err := parseKey(line[1:], ']')
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", n, err)
+			}
+
+			if !isComment(rest) {
+				return nil, fmt.Errorf("%d: unexpected text after table header", n)
+			}
+
+			// Each table can only be defined once.
+			name := strings.Join(parts, "\x00")
+			if tables[name] {
+				return nil, fmt.Errorf("%d: duplicate table %q", n, strings.Join(parts, "."))
+			}
+			tables[name] = true
+
+			if table, err = subTable(doc, parts); err != nil {
+				return nil, fmt.Errorf("%d: %v", n, err)
+			}
+
+			continue
+		}
+
+		// Process a key/value pair.
+		parts, rest, err := parseKey(line, '=')
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+
+		value, rest, err := parseTOMLValue(strings.TrimSpace(rest), false)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+
+		if !isComment(rest) {
+			return nil, fmt.Errorf("%d: unexpected text after value", n)
+		}
+
+		parent, err := subTable(table, parts[:len(parts)-1])
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+
+		key := parts[len(parts)-1]
+		if _, found := parent[key]; found {
+			return nil, fmt.Errorf("%d: duplicate key %q", n, key)
+		}
+
+		parent[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// parseKey parses the dotted key at the start of s up to the terminating
+// byte, = for a key/value pair or ] for a table header, and returns its parts
+// and the text after the terminator. Quoted parts may hold dots and are
+// unquoted.
+func parseKey(s string, term byte) ([]string, string, error) {
+	var parts []string
+	key := s
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+
+		var part string
+		switch s[0] {
+		case '"':
+			v, rest, err := parseTOMLValue(s, false)
+			if err != nil {
+				return nil, "", err
+			}
+			part, s = v.(string), rest
+
+		case '\'':
+			end := strings.IndexByte(s[1:], '\'')
+			if end == -1 {
+				return nil, "", fmt.Errorf("unterminated key")
+			}
+			part, s = s[1:end+1], s[end+2:]
+
+		default:
+			end := strings.IndexAny(s, ".\"'"+string(term))
+			if end == -1 {
+				end = len(s)
+			}
+			part, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+
+		if part == "" {
+			return nil, "", fmt.Errorf("invalid key %q", strings.TrimSpace(key))
+		}
+		parts = append(parts, part)
+
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+
+		switch s[0] {
+		case '.':
+			s = s[1:]
+		case term:
+			return parts, s[1:], nil
+		default:
+			return nil, "", fmt.Errorf("invalid key %q", strings.TrimSpace(key))
+		}
+	}
+
+	if term == ']' {
+		return nil, "", fmt.Errorf("unterminated table header")
+	}
+
+	return nil, "", fmt.Errorf("expected key = value")
+}
+
+// subTable returns the table found by following the parts from t, creating
+// any tables that don't exist.
+func subTable(t map[string]interface{}, parts []string) (map[string]interface{}, error) {
+	for _, p := range parts {
+		v, found := t[p]
+		if !found {
+			st := make(map[string]interface{})
+			t[p] = st
+			t = st
+			continue
+		}
+
+		st, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("key %q is not a table", p)
+		}
+		t = st
+	}
+
+	return t, nil
+}
+
+// parseTOMLValue parses the value at the start of s and returns the text that
+// follows it. Bare values inside an array end at the next comma or bracket.
+func parseTOMLValue(s string, inArray bool) (interface{}, string, error) {
+	if s == "" {
+		return "", "", nil
+	}
+
+	switch s[0] {
+	case '"':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				v, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return nil, "", fmt.Errorf("invalid string %s", s[:i+1])
+				}
+				return v, s[i+1:], nil
+			}
+		}
+		return nil, "", fmt.Errorf("unterminated string")
+
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end == -1 {
+			return nil, "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+
+	case '[':
+		return parseTOMLArray(s[1:])
+	}
+
+	// Bare values end at a comment or, inside an array, at the next element.
+	end := len(s)
+	for i := 0; i < len(s); i++ {
+		if inArray && (s[i] == ',' || s[i] == ']') {
+			end = i
+			break
+		}
+
+		if (s[i] == '#' || s[i] == ';') && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+			end = i
+			break
+		}
+	}
+
+	return strings.TrimSpace(s[:end]), s[end:], nil
+}
+
+// parseTOMLArray parses the elements of an array up to and including the
+// closing bracket and returns the text that follows it.
+func parseTOMLArray(s string) (interface{}, string, error) {
+	arr := []interface{}{}
+
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, "", fmt.Errorf("unterminated array")
+		}
+
+		if s[0] == ']' {
+			return arr, s[1:], nil
+		}
+
+		v, rest, err := parseTOMLValue(s, true)
+		if err != nil {
+			return nil, "", err
+		}
+		arr = append(arr, v)
+
+		rest = strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(rest, ","):
+			s = rest[1:]
+		case strings.HasPrefix(rest, "]"):
+			s = rest
+		default:
+			return nil, "", fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+// isComment reports whether the text is empty or only holds a comment.
+func isComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s[0] == '#' || s[0] == ';'
+}
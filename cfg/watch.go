@@ -0,0 +1,251 @@
+package cfg
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// subscription is a callback registered with OnChange.
+type subscription struct {
+	keys map[string]bool
+	fn   func(old, new map[string]string)
+}
+
+// OnChange registers fn to be called whenever any of the given keys is added,
+// modified or removed by a Reload or one of the Set functions. An empty list of
+// keys subscribes to every key. The old and new maps only contain the
+// subscribed keys that changed; a key missing from old was added and a key
+// missing from new was removed. Callbacks are called synchronously after the
+// Config has been updated, so they can safely read from the Config.
+func (c *Config) OnChange(keys []string, fn func(old, new map[string]string)) {
+	if c.parent != nil {
+		c.parent.OnChange(prefixKeys(keys, c.prefix), func(old, new map[string]string) {
+			o, n := trimKeys(old, c.prefix), trimKeys(new, c.prefix)
+			if len(o) == 0 && len(n) == 0 {
+				return
+			}
+
+			fn(o, n)
+		})
+		return
+	}
+
+	s := subscription{fn: fn}
+
+	if len(keys) > 0 {
+		s.keys = make(map[string]bool, len(keys))
+		for _, k := range keys {
+			s.keys[k] = true
+		}
+	}
+
+	c.mu.Lock()
+	{
+		c.subs = append(c.subs, s)
+	}
+	c.mu.Unlock()
+}
+
+// Reload reads the configuration from the Provider again and atomically
+// replaces the current values. Values added with one of the Set functions are
+// discarded. Callbacks registered with OnChange are notified of any keys that
+// changed. It will return an error if there was any problem reading from the
+// Provider or the values contain a reference cycle, in which case the current
+// values are kept. A view created with Sub can't be reloaded, reload the
+// Config it was created from instead.
+func (c *Config) Reload(p Provider) error {
+	if c.parent != nil {
+		return errView
+	}
+
+	pv, err := provide(p)
+	if err != nil {
+		return err
+	}
+
+	return c.reload(pv)
+}
+
+// errView is returned when reloading or watching a view created with Sub.
+var errView = errors.New("a view can't be reloaded")
+
+// reload replaces the current values with the values read from a Provider.
+func (c *Config) reload(pv provided) error {
+	c.mu.Lock()
+	m, err := resolve(pv.m, c.env, c.dec)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	old := c.m
+	c.m = m
+	c.raw = pv.m
+	c.read = pv.m
+	c.src = pv.src
+	c.secret = pv.secret
+	subs := c.subs
+	c.mu.Unlock()
+
+	notify(subs, old, m)
+
+	return nil
+}
+
+// Watch polls the Provider at the given interval and reloads the Config when
+// the configuration changes. File based Providers are only read again when the
+// modification time or contents of their file change, other Providers are read
+// on every interval. The Config is only reloaded when the values read differ
+// from the values last read, so values added with one of the Set functions
+// are kept until the configuration changes. The first poll always reads the
+// Provider so changes made before Watch was called are picked up. If the
+// Provider fails to read, the current values are kept and the read is retried
+// on the next interval. Watch blocks until the context is done and returns
+// the context's error. A view created with Sub can't be watched and an error
+// is returned at once:
+//
+//	go c.Watch(ctx, cfg.FileProvider{Filename: "app.env"}, 5*time.Second)
+func (c *Config) Watch(ctx context.Context, p Provider, interval time.Duration) error {
+	if c.parent != nil {
+		return errView
+	}
+
+	var last string
+	var ok bool
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		fp, fok := fingerprint(p)
+		if ok && fok && fp == last {
+			continue
+		}
+
+		pv, err := provide(p)
+		if err != nil {
+			continue
+		}
+
+		c.mu.RLock()
+		same := reflect.DeepEqual(pv.m, c.read)
+		c.mu.RUnlock()
+
+		if !same {
+			if err := c.reload(pv); err != nil {
+				continue
+			}
+		}
+
+		last, ok = fp, fok
+	}
+}
+
+// fingerprint returns a value that changes whenever the configuration of the
+// Provider may have changed. It returns false when the Provider can't be
+// fingerprinted and must be read on every poll.
+func fingerprint(p Provider) (string, bool) {
+	switch p := p.(type) {
+	case FileProvider:
+		return fileFingerprint(p.Filename)
+
+	case JSONProvider:
+		return fileFingerprint(p.Filename)
+
+	case TOMLProvider:
+		return fileFingerprint(p.Filename)
+
+	case YAMLProvider:
+		return fileFingerprint(p.Filename)
+
+	case MultiProvider:
+		fps := make([]string, len(p.Providers))
+		for i, sp := range p.Providers {
+			fp, ok := fingerprint(sp)
+			if !ok {
+				return "", false
+			}
+			fps[i] = fp
+		}
+
+		return strings.Join(fps, "|"), true
+	}
+
+	return "", false
+}
+
+// fileFingerprint returns the modification time and hash of the file.
+func fileFingerprint(filename string) (string, bool) {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return "", false
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d:%x", fi.ModTime().UnixNano(), sha256.Sum256(data)), true
+}
+
+// notify calls each subscription with the subscribed keys that differ between
+// old and new.
+func notify(subs []subscription, old, new map[string]string) {
+	for _, s := range subs {
+		o, n := changes(old, new, s.keys)
+		if len(o) == 0 && len(n) == 0 {
+			continue
+		}
+
+		s.fn(o, n)
+	}
+}
+
+// changes returns the values from old and new for every key that was added,
+// modified or removed. When keys is not nil, only those keys are considered.
+func changes(old, new map[string]string, keys map[string]bool) (map[string]string, map[string]string) {
+	o := make(map[string]string)
+	n := make(map[string]string)
+
+	for k, ov := range old {
+		if keys != nil && !keys[k] {
+			continue
+		}
+
+		nv, found := new[k]
+		if found && nv == ov {
+			continue
+		}
+
+		o[k] = ov
+		if found {
+			n[k] = nv
+		}
+	}
+
+	for k, nv := range new {
+		if keys != nil && !keys[k] {
+			continue
+		}
+
+		if _, found := old[k]; !found {
+			n[k] = nv
+		}
+	}
+
+	return o, n
+}
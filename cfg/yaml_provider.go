@@ -0,0 +1,475 @@
+package cfg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// YAMLProvider describes a file based loader which loads the configuration
+// from a YAML document. It supports the subset of YAML that is commonly used
+// for configuration files:
+//
+//	# Comments start with #.
+//	name: kit
+//	debug: true
+//	db:
+//	  host: "localhost"
+//	  pool:
+//	    max: 10
+//	  hosts: [a.local, 'b.local']
+//	servers:
+//	  - host: s1
+//	  - host: s2
+//
+// Nested mappings are flattened into keys joined with Separator, which
+// defaults to ".", and are made uppercase when Uppercase is set, the same as
+// the JSONProvider. Values may be plain, "double quoted" with escapes or
+// 'single quoted' scalars, single line [flow] sequences or block sequences.
+// Sequences of scalars are stored as a comma separated list that can be read
+// with StringSlice, other sequences are flattened using the index of each
+// element as a key. A null or ~ value is stored as an empty string. Indenting
+// with tabs, flow mappings, block scalars such as | and >, anchors, aliases,
+// tags and multiple documents are not supported and are reported as errors.
+type YAMLProvider struct {
+	Filename  string
+	Separator string
+	Uppercase bool
+}
+
+// String implements the fmt.Stringer interface and names the Provider when
+// reporting the source of a key.
+func (yp YAMLProvider) String() string {
+	return "yaml:" + yp.Filename
+}
+
+// Provide implements the Provider interface.
+func (yp YAMLProvider) Provide() (map[string]string, error) {
+	file, err := os.Open(yp.Filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := parseYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", yp.Filename, err)
+	}
+
+	f := newFlattener(yp.Separator, yp.Uppercase)
+	f.add("", doc)
+
+	return f.m, nil
+}
+
+// yamlLine is a line of a YAML document that holds content.
+type yamlLine struct {
+	n      int
+	indent int
+	text   string
+}
+
+// parseYAML reads the document into nested maps. Errors are prefixed with
+// the line number they occurred on.
+func parseYAML(r io.Reader) (map[string]interface{}, error) {
+	var lines []yamlLine
+
+	scanner := bufio.NewScanner(r)
+
+	var n int
+	for scanner.Scan() {
+		n++
+
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		if text[0] == '\t' {
+			return nil, fmt.Errorf("%d: tabs are not allowed for indentation", n)
+		}
+
+		// Only a single document is supported.
+		if text == "---" || strings.HasPrefix(text, "--- ") {
+			if len(lines) > 0 {
+				return nil, fmt.Errorf("%d: multiple documents are not supported", n)
+			}
+			continue
+		}
+		if text == "..." {
+			break
+		}
+
+		lines = append(lines, yamlLine{n: n, indent: len(raw) - len(text), text: text})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return make(map[string]interface{}), nil
+	}
+
+	if isSequenceItem(lines[0].text) {
+		return nil, fmt.Errorf("%d: the document must be a mapping", lines[0].n)
+	}
+
+	doc, next, err := parseYAMLMapping(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+
+	if next < len(lines) {
+		return nil, fmt.Errorf("%d: unexpected indentation", lines[next].n)
+	}
+
+	return doc, nil
+}
+
+// parseYAMLBlock parses the mapping or sequence that starts at line i with
+// the given indentation and returns the index of the line that follows it.
+func parseYAMLBlock(lines []yamlLine, i int, indent int) (interface{}, int, error) {
+	if isSequenceItem(lines[i].text) {
+		return parseYAMLSequence(lines, i, indent)
+	}
+
+	return parseYAMLMapping(lines, i, indent)
+}
+
+// parseYAMLMapping parses the key: value pairs that start at line i with the
+// given indentation and returns the index of the line that follows them.
+func parseYAMLMapping(lines []yamlLine, i int, indent int) (map[string]interface{}, int, error) {
+	m := make(map[string]interface{})
+
+	for i < len(lines) && lines[i].indent == indent {
+		l := lines[i]
+
+		if isSequenceItem(l.text) {
+			return nil, 0, fmt.Errorf("%d: expected key: value", l.n)
+		}
+
+		key, rest, err := parseYAMLKey(l.text)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%d: %v", l.n, err)
+		}
+
+		if _, found := m[key]; found {
+			return nil, 0, fmt.Errorf("%d: duplicate key %q", l.n, key)
+		}
+
+		i++
+
+		if !isYAMLComment(rest) {
+			v, err := parseYAMLScalar(rest)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%d: %v", l.n, err)
+			}
+			m[key] = v
+			continue
+		}
+
+		// A key without a value holds the block indented below it, or a
+		// sequence at the same indentation.
+		switch {
+		case i < len(lines) && lines[i].indent > indent:
+			v, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key], i = v, next
+
+		case i < len(lines) && lines[i].indent == indent && isSequenceItem(lines[i].text):
+			v, next, err := parseYAMLSequence(lines, i, indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key], i = v, next
+
+		default:
+			m[key] = ""
+		}
+	}
+
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("%d: unexpected indentation", lines[i].n)
+	}
+
+	return m, i, nil
+}
+
+// parseYAMLSequence parses the - items that start at line i with the given
+// indentation and returns the index of the line that follows them.
+func parseYAMLSequence(lines []yamlLine, i int, indent int) ([]interface{}, int, error) {
+	seq := []interface{}{}
+
+	for i < len(lines) && lines[i].indent == indent && isSequenceItem(lines[i].text) {
+		l := lines[i]
+		item := strings.TrimLeft(l.text[1:], " ")
+
+		switch {
+
+		// The item is a block indented on the following lines.
+		case isYAMLComment(item):
+			i++
+			if i == len(lines) || lines[i].indent <= indent {
+				seq = append(seq, "")
+				continue
+			}
+
+			v, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			seq, i = append(seq, v), next
+
+		// The item starts a mapping or a sequence on the same line, which
+		// continues on the lines indented to the same column.
+		case isSequenceItem(item) || isYAMLMapping(item):
+			lines[i] = yamlLine{n: l.n, indent: indent + len(l.text) - len(item), text: item}
+
+			v, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			seq, i = append(seq, v), next
+
+		default:
+			v, err := parseYAMLScalar(item)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%d: %v", l.n, err)
+			}
+			seq = append(seq, v)
+			i++
+		}
+	}
+
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("%d: unexpected indentation", lines[i].n)
+	}
+
+	return seq, i, nil
+}
+
+// parseYAMLKey parses the key at the start of s and returns the text that
+// follows the colon after it.
+func parseYAMLKey(s string) (string, string, error) {
+	if s[0] == '"' || s[0] == '\'' {
+		key, rest, err := parseYAMLQuoted(s)
+		if err != nil {
+			return "", "", err
+		}
+
+		if rest == "" || rest[0] != ':' || (len(rest) > 1 && rest[1] != ' ') {
+			return "", "", fmt.Errorf("expected key: value")
+		}
+
+		return key, strings.TrimSpace(rest[1:]), nil
+	}
+
+	idx := yamlColon(s)
+	if idx == -1 {
+		return "", "", fmt.Errorf("expected key: value")
+	}
+
+	key := strings.TrimSpace(s[:idx])
+	if key == "" || strings.ContainsAny(key[:1], "[]{}&*!|>%@`#") {
+		return "", "", fmt.Errorf("invalid key %q", key)
+	}
+
+	return key, strings.TrimSpace(s[idx+1:]), nil
+}
+
+// parseYAMLScalar parses a scalar or a flow sequence, which must be the only
+// value on the line apart from a comment.
+func parseYAMLScalar(s string) (interface{}, error) {
+	switch s[0] {
+	case '"', '\'':
+		v, rest, err := parseYAMLQuoted(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isYAMLComment(rest) {
+			return nil, fmt.Errorf("unexpected text after value")
+		}
+
+		return v, nil
+
+	case '[':
+		return parseYAMLFlow(s[1:])
+
+	case '{':
+		return nil, fmt.Errorf("flow mappings are not supported")
+
+	case '|', '>':
+		return nil, fmt.Errorf("block scalars are not supported")
+
+	case '&', '*', '!':
+		return nil, fmt.Errorf("anchors, aliases and tags are not supported")
+	}
+
+	return plainYAML(s, false), nil
+}
+
+// parseYAMLFlow parses the elements of a flow sequence up to and including
+// the closing bracket.
+func parseYAMLFlow(s string) (interface{}, error) {
+	seq := []interface{}{}
+
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, fmt.Errorf("unterminated sequence")
+		}
+
+		if s[0] == ']' {
+			s = s[1:]
+			break
+		}
+
+		var v string
+		switch s[0] {
+		case '"', '\'':
+			qv, rest, err := parseYAMLQuoted(s)
+			if err != nil {
+				return nil, err
+			}
+			v, s = qv, strings.TrimSpace(rest)
+
+		case '[', '{':
+			return nil, fmt.Errorf("nested flow collections are not supported")
+
+		default:
+			end := strings.IndexAny(s, ",]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated sequence")
+			}
+			v, s = plainYAML(s[:end], true), s[end:]
+		}
+		seq = append(seq, v)
+
+		if s == "" {
+			return nil, fmt.Errorf("unterminated sequence")
+		}
+
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+
+		if s[0] != ']' {
+			return nil, fmt.Errorf("expected , or ] in sequence")
+		}
+
+		s = s[1:]
+		break
+	}
+
+	if !isYAMLComment(s) {
+		return nil, fmt.Errorf("unexpected text after value")
+	}
+
+	return seq, nil
+}
+
+// parseYAMLQuoted parses the quoted string at the start of s and returns the
+// text that follows it. Double quoted strings support escapes and a single
+// quote is written twice inside a single quoted string.
+func parseYAMLQuoted(s string) (string, string, error) {
+	if s[0] == '"' {
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				v, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return "", "", fmt.Errorf("invalid string %s", s[:i+1])
+				}
+				return v, strings.TrimLeft(s[i+1:], " "), nil
+			}
+		}
+		return "", "", fmt.Errorf("unterminated string")
+	}
+
+	var buf strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == '\'' {
+			buf.WriteByte('\'')
+			i++
+			continue
+		}
+
+		return buf.String(), strings.TrimLeft(s[i+1:], " "), nil
+	}
+
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+// plainYAML returns the value of a plain scalar, removing a trailing comment.
+// Null values are returned as an empty string.
+func plainYAML(s string, inFlow bool) string {
+	if !inFlow {
+		if idx := strings.Index(s, " #"); idx != -1 {
+			s = s[:idx]
+		}
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "~" || s == "null" || s == "Null" || s == "NULL" {
+		return ""
+	}
+
+	return s
+}
+
+// yamlColon returns the index of the colon that ends a plain key, which is
+// followed by a space or the end of the line, or -1 if there is none.
+func yamlColon(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ') {
+			return i
+		}
+
+		if s[i] == '#' && i > 0 && s[i-1] == ' ' {
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// isSequenceItem reports whether the text starts a - sequence item.
+func isSequenceItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// isYAMLMapping reports whether the text starts a key: value pair.
+func isYAMLMapping(s string) bool {
+	if s[0] == '"' || s[0] == '\'' {
+		_, rest, err := parseYAMLQuoted(s)
+		return err == nil && strings.HasPrefix(rest, ":")
+	}
+
+	if strings.ContainsAny(s[:1], "[{&*!|>") {
+		return false
+	}
+
+	return yamlColon(s) != -1
+}
+
+// isYAMLComment reports whether the text is empty or only holds a comment.
+func isYAMLComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s[0] == '#'
+}
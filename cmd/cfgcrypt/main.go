@@ -0,0 +1,112 @@
+// Program cfgcrypt encrypts and decrypts configuration values for use with
+// the cfg package. The key is read from a file or an environment variable and
+// holds a base64 encoded AES key.
+//
+//	cfgcrypt genkey > key.txt
+//	cfgcrypt -key-file key.txt encrypt DB_PASS 'my secret'
+//	cfgcrypt -key-env APP_KEY decrypt DB_PASS enc:v1:...
+//
+// The name of the configuration key is bound to the encrypted value, so the
+// value only decrypts when it is stored under that key. When no value is
+// given on the command line it is read from stdin.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ardanlabs/kit/cfg"
+)
+
+func main() {
+	keyFile := flag.String("key-file", "", "File holding the base64 encoded key.")
+	keyEnv := flag.String("key-env", "", "Environment variable holding the base64 encoded key.")
+
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: cfgcrypt [flags] genkey | encrypt key [value] | decrypt key [value]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), *keyFile, *keyEnv); err != nil {
+		fmt.Fprintln(os.Stderr, "cfgcrypt:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command given in args.
+func run(args []string, keyFile, keyEnv string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "genkey" {
+		key, err := cfg.NewKey()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(key)
+		return nil
+	}
+
+	var kp cfg.KeyProvider
+	switch {
+	case keyFile != "":
+		kp = cfg.KeyFile{Filename: keyFile}
+	case keyEnv != "":
+		kp = cfg.KeyEnv{Name: keyEnv}
+	default:
+		return fmt.Errorf("one of -key-file or -key-env is required")
+	}
+
+	aes, err := cfg.NewAESGCM(kp)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("the name of the key is required")
+	}
+
+	value, err := input(args[2:])
+	if err != nil {
+		return err
+	}
+
+	var out string
+	switch args[0] {
+	case "encrypt":
+		out, err = cfg.EncryptValue(aes, args[1], value)
+	case "decrypt":
+		out, err = cfg.DecryptValue(aes, args[1], value)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+	return nil
+}
+
+// input returns the value from the command line or from stdin.
+func input(args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+
+	data, err := ioutil.ReadAll(bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
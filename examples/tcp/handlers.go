@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"net"
+	"time"
 
 	"github.com/ardanlabs/kit/log"
 	"github.com/ardanlabs/kit/tcp"
@@ -43,7 +44,7 @@ func (tcpReqHandler) Read(ipAddress string, reader io.Reader) ([]byte, int, erro
 // is called on a routine from a pool of routines.
 func (tcpReqHandler) Process(r *tcp.Request) {
 	log.Dev("handler", "Process", "Started : IP[%s] Length[%d] ReadAt[%v]", r.TCPAddr.String(), r.Length, r.ReadAt)
-	log.User("handler", "Process", "Data : %s", string(r.Data))
+	log.Limit(r.TCPAddr.String(), 10, time.Second).User("handler", "Process", "Data : %s", string(r.Data))
 
 	resp := tcp.Response{
 		TCPAddr: r.TCPAddr,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"time"
 
 	"github.com/ardanlabs/kit/cfg"
 	"github.com/ardanlabs/kit/log"
@@ -37,7 +38,11 @@ func init() {
 		}
 		return ll
 	}
-	log.Init(os.Stderr, logLevel, log.Ldefault)
+
+	// Sample the messages logged for every request so a busy server doesn't
+	// flood the output, and report what was dropped once a minute.
+	sampling := log.Sampling{First: 100, Thereafter: 100}
+	log.Init(os.Stderr, logLevel, log.Ldefault, log.WithSampling(sampling), log.WithSummary(time.Minute))
 
 	// Log all the configuration options
 	log.User("startup", "init", "\n\nConfig Settings: %s\n%s\n", configKey, cfg.Log())
@@ -0,0 +1,277 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned when writing to an Async writer that was closed.
+var ErrClosed = errors.New("log writer closed")
+
+// Policy selects what an Async writer does with a new entry when its buffer
+// is full.
+type Policy int
+
+// Set of policies for a full Async buffer.
+const (
+	// Block waits for room in the buffer.
+	Block Policy = iota
+
+	// DropNewest discards the new entry.
+	DropNewest
+
+	// DropOldest discards the oldest entry in the buffer to make room.
+	DropOldest
+)
+
+// AsyncConfig provides the settings for an Async writer.
+type AsyncConfig struct {
+	Size          int           // Number of entries the buffer holds, defaults to 1024.
+	Policy        Policy        // What to do when the buffer is full, defaults to Block.
+	FlushInterval time.Duration // How often buffered output is flushed, defaults to 1s.
+}
+
+// Async is an io.Writer that queues each write in a bounded ring buffer and
+// writes it to the underlying writer from a separate goroutine, so logging
+// doesn't wait on the output. Each call to Write is treated as one entry, as
+// is the case when an Async is given to New or Init:
+//
+//	a := log.NewAsync(os.Stderr, log.AsyncConfig{Size: 4096, Policy: log.DropOldest})
+//	defer a.Close()
+//
+//	log.Init(a, logLevel, log.Ldefault)
+//
+// When the underlying writer is an EntryWriter, such as a Multi, Syslog or
+// Journal, the Logger gives the Async each entry instead of encoding it and
+// the queued entries are passed on with WriteEntry.
+//
+// Output is buffered and flushed every FlushInterval, when Flush is called and
+// when the Async is closed.
+type Async struct {
+	w       *bufio.Writer
+	ew      EntryWriter
+	policy  Policy
+	dropped uint64
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	ring    []queued
+	head    int
+	n       int
+	closed  bool
+
+	wake    chan struct{}
+	flush   chan chan error
+	quit    chan chan error
+	stopped chan struct{}
+}
+
+// NewAsync returns an Async writing to w and starts its goroutine. Close must
+// be called to stop the goroutine and write the queued entries.
+func NewAsync(w io.Writer, cfg AsyncConfig) *Async {
+	if cfg.Size <= 0 {
+		cfg.Size = 1024
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	a := Async{
+		w:       bufio.NewWriter(w),
+		policy:  cfg.Policy,
+		ring:    make([]queued, cfg.Size),
+		wake:    make(chan struct{}, 1),
+		flush:   make(chan chan error),
+		quit:    make(chan chan error),
+		stopped: make(chan struct{}),
+	}
+	a.notFull = sync.NewCond(&a.mu)
+
+	if ew, ok := w.(EntryWriter); ok {
+		a.ew = ew
+	}
+
+	go a.run(cfg.FlushInterval)
+
+	return &a
+}
+
+// queued is an item in the ring buffer, either the bytes of a Write or an
+// entry given to WriteEntry.
+type queued struct {
+	p     []byte
+	e     Entry
+	entry bool
+}
+
+// Write implements the io.Writer interface. It queues a copy of p according
+// to the Policy and returns without waiting for it to be written. It returns
+// ErrClosed once the Async is closed.
+func (a *Async) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	if err := a.enqueue(queued{p: data}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntry implements the EntryWriter interface. It queues the entry
+// according to the Policy for the underlying EntryWriter and returns without
+// waiting for it to be written. It returns ErrClosed once the Async is closed.
+func (a *Async) WriteEntry(e Entry) error {
+	if a.ew == nil {
+		return errors.New("log writer doesn't accept entries")
+	}
+
+	return a.enqueue(queued{e: e, entry: true})
+}
+
+// entries implements the entryOutput interface. The Logger only gives the
+// Async entries when the underlying writer accepts them.
+func (a *Async) entries() bool {
+	return a.ew != nil
+}
+
+// enqueue adds the item to the ring buffer according to the Policy.
+func (a *Async) enqueue(q queued) error {
+	a.mu.Lock()
+	{
+		for a.n == len(a.ring) && !a.closed {
+			if a.policy == DropNewest {
+				atomic.AddUint64(&a.dropped, 1)
+				a.mu.Unlock()
+				return nil
+			}
+
+			if a.policy == DropOldest {
+				a.ring[a.head] = queued{}
+				a.head = (a.head + 1) % len(a.ring)
+				a.n--
+				atomic.AddUint64(&a.dropped, 1)
+				break
+			}
+
+			a.notFull.Wait()
+		}
+
+		if a.closed {
+			a.mu.Unlock()
+			return ErrClosed
+		}
+
+		a.ring[(a.head+a.n)%len(a.ring)] = q
+		a.n++
+	}
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Dropped returns the number of entries that were discarded because the
+// buffer was full.
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Flush waits for the queued entries to be written and flushes the output.
+func (a *Async) Flush() error {
+	ch := make(chan error)
+
+	select {
+	case a.flush <- ch:
+		return <-ch
+	case <-a.stopped:
+		return nil
+	}
+}
+
+// Close stops accepting entries, writes the queued entries and flushes the
+// output. Writers blocked on a full buffer are released with ErrClosed. The
+// underlying writer is not closed.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	{
+		if a.closed {
+			a.mu.Unlock()
+			return nil
+		}
+
+		a.closed = true
+		a.notFull.Broadcast()
+	}
+	a.mu.Unlock()
+
+	ch := make(chan error)
+	a.quit <- ch
+
+	return <-ch
+}
+
+// run writes the queued entries as they arrive and flushes the output on
+// every interval until the Async is closed.
+func (a *Async) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(a.stopped)
+
+	for {
+		select {
+		case <-a.wake:
+			a.drain()
+
+		case <-ticker.C:
+			a.drain()
+			a.w.Flush()
+
+		case ch := <-a.flush:
+			a.drain()
+			ch <- a.w.Flush()
+
+		case ch := <-a.quit:
+			a.drain()
+			ch <- a.w.Flush()
+			return
+		}
+	}
+}
+
+// drain writes every queued item to the buffered output. Entries are given
+// to the underlying EntryWriter after the bytes buffered before them are
+// flushed, so the order is kept.
+func (a *Async) drain() {
+	a.mu.Lock()
+	items := make([]queued, a.n)
+	for i := range items {
+		idx := (a.head + i) % len(a.ring)
+		items[i] = a.ring[idx]
+		a.ring[idx] = queued{}
+	}
+	a.head, a.n = 0, 0
+	a.notFull.Broadcast()
+	a.mu.Unlock()
+
+	for _, q := range items {
+		if !q.entry {
+			a.w.Write(q.p)
+			continue
+		}
+
+		if a.w.Buffered() > 0 {
+			a.w.Flush()
+		}
+		a.ew.WriteEntry(q.e)
+	}
+}
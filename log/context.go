@@ -0,0 +1,181 @@
+package log
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// ctxKey represents the type of value for the context keys.
+type ctxKey int
+
+// Set of context keys used to store values for logging.
+const (
+	keyLogger ctxKey = iota + 1
+	keyTraceID
+	keyFields
+)
+
+// WithContext returns a copy of ctx holding the Logger, which is returned by
+// FromContext and used by the package level Ctx functions.
+func WithContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, keyLogger, l)
+}
+
+// FromContext returns the Logger held by ctx, or the default logger if ctx
+// doesn't hold one.
+func FromContext(ctx context.Context) *Logger {
+	if lg, ok := ctx.Value(keyLogger).(*Logger); ok {
+		return lg
+	}
+
+	return &l
+}
+
+// WithTraceID returns a copy of ctx holding the trace ID used by the Ctx
+// functions. The web package stores the trace ID of each request this way.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, keyTraceID, traceID)
+}
+
+// TraceID returns the trace ID held by ctx, or an empty string.
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(keyTraceID).(string)
+	return traceID
+}
+
+// WithFields returns a copy of ctx with a list of alternating keys and values,
+// or Fields, attached. They are added to the fields already attached to ctx
+// and included in every entry logged by the Ctx functions.
+func WithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	attached := ctxFields(ctx)
+
+	fs := make([]Field, 0, len(attached)+len(keysAndValues))
+	fs = append(fs, attached...)
+	fs = append(fs, fields(keysAndValues)...)
+
+	return context.WithValue(ctx, keyFields, fs)
+}
+
+// ctxFields returns the fields attached to ctx.
+func ctxFields(ctx context.Context) []Field {
+	fs, _ := ctx.Value(keyFields).([]Field)
+	return fs
+}
+
+// TraceCtx logs detailed trace information for developers using the trace ID
+// and fields from ctx. The function name is taken from the caller.
+func (l *Logger) TraceCtx(ctx context.Context, format string, a ...interface{}) {
+	l.logf(mLevel, TRACE, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// DevCtx logs trace information for developers using the trace ID and fields
+// from ctx. The function name is taken from the caller.
+func (l *Logger) DevCtx(ctx context.Context, format string, a ...interface{}) {
+	l.logf(mLevel, DEV, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// UserCtx logs trace information for users using the trace ID and fields from
+// ctx. The function name is taken from the caller.
+func (l *Logger) UserCtx(ctx context.Context, format string, a ...interface{}) {
+	l.logf(mLevel, USER, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// WarnCtx logs trace information for users about unexpected situations using
+// the trace ID and fields from ctx. The function name is taken from the
+// caller.
+func (l *Logger) WarnCtx(ctx context.Context, format string, a ...interface{}) {
+	l.logf(mLevel, WARN, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// ErrorCtx logs trace information that are errors using the trace ID and
+// fields from ctx. The function name is taken from the caller.
+func (l *Logger) ErrorCtx(ctx context.Context, err error, format string, a ...interface{}) {
+	l.logf(mLevel, ERROR, TraceID(ctx), "", err, format, a, ctxFields(ctx))
+}
+
+// FatalCtx logs trace information for users using the trace ID and fields
+// from ctx, runs the exit hooks and terminates the app. The function name is
+// taken from the caller.
+func (l *Logger) FatalCtx(ctx context.Context, format string, a ...interface{}) {
+	l.logf(mLevel, FATAL, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+
+	l.terminate()
+}
+
+// PanicCtx logs trace information for users using the trace ID and fields
+// from ctx and panics with the message. The function name is taken from the
+// caller.
+func (l *Logger) PanicCtx(ctx context.Context, format string, a ...interface{}) {
+	msg := sprintf(format, a)
+	l.logf(mLevel, PANIC, TraceID(ctx), "", nil, msg, nil, ctxFields(ctx))
+
+	panic(msg)
+}
+
+// TraceCtx logs detailed trace information for developers with the Logger,
+// trace ID and fields from ctx.
+func TraceCtx(ctx context.Context, format string, a ...interface{}) {
+	FromContext(ctx).logf(mLevel, TRACE, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// DevCtx logs trace information for developers with the Logger, trace ID and
+// fields from ctx.
+func DevCtx(ctx context.Context, format string, a ...interface{}) {
+	FromContext(ctx).logf(mLevel, DEV, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// UserCtx logs trace information for users with the Logger, trace ID and
+// fields from ctx.
+func UserCtx(ctx context.Context, format string, a ...interface{}) {
+	FromContext(ctx).logf(mLevel, USER, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// WarnCtx logs trace information for users about unexpected situations with
+// the Logger, trace ID and fields from ctx.
+func WarnCtx(ctx context.Context, format string, a ...interface{}) {
+	FromContext(ctx).logf(mLevel, WARN, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+}
+
+// ErrorCtx logs trace information that are errors with the Logger, trace ID
+// and fields from ctx.
+func ErrorCtx(ctx context.Context, err error, format string, a ...interface{}) {
+	FromContext(ctx).logf(mLevel, ERROR, TraceID(ctx), "", err, format, a, ctxFields(ctx))
+}
+
+// FatalCtx logs trace information for users with the Logger, trace ID and
+// fields from ctx, runs the exit hooks and terminates the app.
+func FatalCtx(ctx context.Context, format string, a ...interface{}) {
+	lg := FromContext(ctx)
+	lg.logf(mLevel, FATAL, TraceID(ctx), "", nil, format, a, ctxFields(ctx))
+
+	lg.terminate()
+}
+
+// PanicCtx logs trace information for users with the Logger, trace ID and
+// fields from ctx and panics with the message.
+func PanicCtx(ctx context.Context, format string, a ...interface{}) {
+	msg := sprintf(format, a)
+	FromContext(ctx).logf(mLevel, PANIC, TraceID(ctx), "", nil, msg, nil, ctxFields(ctx))
+
+	panic(msg)
+}
+
+// funcName returns the name of the function for the program counter in the
+// form used by the log calls, Func or Type.Method, without the package.
+func funcName(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "???"
+	}
+
+	name := fn.Name()
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		name = name[idx+1:]
+	}
+	if idx := strings.Index(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(name)
+}
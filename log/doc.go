@@ -1,7 +1,18 @@
-// Package log provides a simple layer above the standard library logging package.
-// The base API provides two logging levels, DEV and USER. The DEV level logs things
-// developers need and can be verbose. The USER level logs things for users need
-// and should not be verbose. There is an Error call which falls under USER.
+// Package log provides leveled, structured logging for applications and the
+// kit packages. Each level has its own call:
+//
+//		TRACE  Detailed tracing for developers, the most verbose.
+//		DEV    Things developers need, can be verbose. Also named DEBUG.
+//		USER   Things users need, should not be verbose. Also named INFO.
+//		WARN   Unexpected situations the app recovered from.
+//		ERROR  Errors, logged along with the error value.
+//		FATAL  Errors the app can't recover from, the app is terminated.
+//		PANIC  Errors that end the goroutine, the call panics after logging.
+//
+// The level handler given to Init returns the lowest level to log, which
+// enables that level and every level after it in the list above. NONE
+// disables logging. To keep the original values of NONE, DEV and USER, TRACE
+// is -1 and sorts below NONE, so compare a level to NONE with == only.
 //
 // To initialize the logging system from your application, call Init:
 //
@@ -20,7 +31,7 @@
 //		log.Dev(ctx, "CreateUser", "Started : Email[%s]", nu.Email)
 //		log.Error(ctx, "CreateUser", err, "Completed")
 //
-// The API for Dev and User follow this convention:
+// The calls for every level follow this convention:
 //
 //		log.User(ctx, "funcName", "formatted message %s", values)
 //
@@ -39,4 +50,106 @@
 //
 // Any string can be provided but it does support a formatted message. Values
 // would be substituted if provided. This messaging is up to you.
+//
+// Structured messages
+//
+// The Devw, Userw, Errorw and Fatalw calls take a message followed by a list
+// of alternating keys and values, or typed Fields, so log pipelines can index
+// on the fields rather than parse the message:
+//
+//		log.Userw(ctx, "CreateUser", "Completed", "email", nu.Email, log.Duration("took", d))
+//
+// Context
+//
+// The Ctx calls take the trace ID and any fields attached with WithFields from
+// a context.Context and use the name of the calling function. The web package
+// stores the trace ID of each request in its context:
+//
+//		ctx = log.WithFields(ctx, "email", nu.Email)
+//		log.UserCtx(ctx, "Started")
+//
+// Named loggers
+//
+// A subsystem can log through a named child logger whose level is set
+// independently of the parent, for example from a configuration key:
+//
+//		tcpLog := log.Named("tcp")
+//		tcpLog.SetLevel(log.LevelFrom(cfg.String, "LOG_LEVEL_TCP", log.USER))
+//
+// Encoders
+//
+// Each entry is formatted by an Encoder. The default TextEncoder writes the
+// format shown above, the JSONEncoder and LogfmtEncoder write the timestamp,
+// level, trace ID, function name, caller, message and fields as separate keys:
+//
+//		log.Init(os.Stderr, logLevel, log.Ldefault, log.WithEncoder(log.JSONEncoder{}))
+//
+// Async output
+//
+// An Async writer queues entries in a bounded buffer and writes them from its
+// own goroutine. When the buffer is full it blocks, drops the new entry or
+// drops the oldest one, and counts what was dropped:
+//
+//		a := log.NewAsync(os.Stderr, log.AsyncConfig{Policy: log.DropOldest})
+//		defer a.Close()
+//
+//		log.Init(a, logLevel, log.Ldefault)
+//
+// An Async can wrap a Multi, Syslog or Journal, the entries are queued and
+// passed on to them as entries.
+//
+// Rotating files
+//
+// A RotatingFile writes to a file and rotates it by size or age, compressing
+// and pruning the rotated files, so no external logrotate is needed:
+//
+//		rf, err := log.NewRotatingFile(log.RotateConfig{
+//			Filename:       "/var/log/app/app.log",
+//			MaxSize:        100 << 20,
+//			Compress:       true,
+//			MaxAge:         7 * 24 * time.Hour,
+//			ReopenOnHangup: true,
+//		})
+//
+// Sampling
+//
+// Hot paths can be kept from flooding the output by sampling the entries from
+// each call site and by limiting the entries logged for a key, such as the
+// address of a client. A summary of what was suppressed is logged as a WARN:
+//
+//		sampling := log.Sampling{First: 100, Thereafter: 100}
+//		log.Init(os.Stderr, logLevel, log.Ldefault, log.WithSampling(sampling), log.WithSummary(time.Minute))
+//
+//		log.Limit(ipAddress, 10, time.Second).Dev(traceID, "Read", "Completed")
+//
+// Multiple outputs
+//
+// A Multi sends each entry to several sinks, each with its own level and
+// Encoder, such as USER text to stderr and DEV JSON to a file:
+//
+//		m := log.NewMulti(
+//			log.Sink{Writer: os.Stderr, Level: func() int { return log.USER }},
+//			log.Sink{Writer: file, Encoder: log.JSONEncoder{}},
+//		)
+//		log.Init(m, func() int { return log.DEV }, log.Ldefault)
+//
+// Syslog and journald
+//
+// A Syslog sends entries to the local syslog daemon with RFC 5424 framing and
+// a Journal sends them to systemd-journald with its native protocol. Both map
+// the levels to syslog severities, debug for TRACE and DEV, info for USER,
+// warning for WARN, err for ERROR and crit for FATAL and PANIC:
+//
+//		sl, err := log.NewSyslog(log.SyslogConfig{Facility: log.FacilityLocal0})
+//		j, err := log.NewJournal(log.JournalConfig{})
+//
+// Exiting
+//
+// The Fatal calls run the hooks registered with OnExit, most recent first,
+// before terminating the app, so output can be flushed and servers stopped.
+// The Panic calls panic with the message instead. The exit function can be
+// replaced with WithExit to test code that calls Fatal:
+//
+//		log.OnExit(func() { a.Close() })
+//		log.OnExit(func() { t.Stop() })
 package log
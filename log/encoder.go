@@ -0,0 +1,208 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single log message along with the information gathered when it
+// was logged. Entries are formatted for output by an Encoder.
+type Entry struct {
+	Time     time.Time
+	Level    int
+	Name     string
+	TraceID  string
+	FuncName string
+	File     string
+	Line     int
+	Message  string
+	Err      error
+	Fields   []Field
+}
+
+// Caller returns the file name, without its directory, and line number of
+// the code that logged the entry in the form file.go:23.
+func (e Entry) Caller() string {
+	return shortFile(e.File) + ":" + strconv.Itoa(e.Line)
+}
+
+// Encoder is implemented to format an Entry for output. The returned bytes
+// must end in a newline.
+type Encoder interface {
+	Encode(e Entry) ([]byte, error)
+}
+
+// TextEncoder formats entries in the original kit format, with the standard
+// library log header selected by Flags:
+//
+//	2009/01/23 01:23:23 main.go:23: USER : traceID : funcName : message
+//
+// An error follows the function name and fields follow the message as
+// key=value pairs. Entries from a named Logger start with the name in
+// brackets.
+type TextEncoder struct {
+	Flags int
+}
+
+// Encode implements the Encoder interface.
+func (te TextEncoder) Encode(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if te.Flags&(Ldate|Ltime|Lmicroseconds) != 0 {
+		t := e.Time
+		if te.Flags&LUTC != 0 {
+			t = t.UTC()
+		}
+
+		if te.Flags&Ldate != 0 {
+			buf.WriteString(t.Format("2006/01/02 "))
+		}
+
+		if te.Flags&(Ltime|Lmicroseconds) != 0 {
+			if te.Flags&Lmicroseconds != 0 {
+				buf.WriteString(t.Format("15:04:05.000000 "))
+			} else {
+				buf.WriteString(t.Format("15:04:05 "))
+			}
+		}
+	}
+
+	if te.Flags&(Lshortfile|Llongfile) != 0 {
+		file := e.File
+		if te.Flags&Lshortfile != 0 {
+			file = shortFile(file)
+		}
+
+		buf.WriteString(file + ":" + strconv.Itoa(e.Line) + ": ")
+	}
+
+	if e.Name != "" {
+		buf.WriteString("[" + e.Name + "] ")
+	}
+
+	fmt.Fprintf(&buf, "%s : %s : %s : ", LevelName(e.Level), e.TraceID, e.FuncName)
+	if e.Err != nil {
+		fmt.Fprintf(&buf, "%+v : ", e.Err)
+	}
+	buf.WriteString(e.Message)
+
+	if len(e.Fields) > 0 {
+		buf.WriteString(" : " + formatFields(e.Fields))
+	}
+
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// JSONEncoder formats entries as a single line JSON object, one per entry.
+// The fields are added to the object after the standard keys:
+//
+//	{"time":"2009-01-23T01:23:23.123Z","level":"USER","trace_id":"traceID","func":"funcName","caller":"main.go:23","msg":"message"}
+//
+// Errors are written under the "error" key and the name of a named Logger
+// under the "logger" key.
+type JSONEncoder struct{}
+
+// Encode implements the Encoder interface.
+func (JSONEncoder) Encode(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("{")
+
+	writeJSON(&buf, "time", e.Time.Format(time.RFC3339Nano), true)
+	writeJSON(&buf, "level", LevelName(e.Level), false)
+	if e.Name != "" {
+		writeJSON(&buf, "logger", e.Name, false)
+	}
+	writeJSON(&buf, "trace_id", e.TraceID, false)
+	writeJSON(&buf, "func", e.FuncName, false)
+	writeJSON(&buf, "caller", e.Caller(), false)
+	writeJSON(&buf, "msg", e.Message, false)
+
+	if e.Err != nil {
+		writeJSON(&buf, "error", e.Err, false)
+	}
+
+	for _, f := range e.Fields {
+		writeJSON(&buf, f.Key, f.Value, false)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// writeJSON writes the key and value as a JSON object member. Values that
+// can't be marshaled are written as strings.
+func writeJSON(buf *bytes.Buffer, key string, value interface{}, first bool) {
+	if !first {
+		buf.WriteString(",")
+	}
+
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteString(":")
+
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(formatValue(value))
+	}
+	buf.Write(data)
+}
+
+// LogfmtEncoder formats entries as logfmt key=value pairs, one line per
+// entry. The fields are added after the standard keys:
+//
+//	time=2009-01-23T01:23:23.123Z level=USER trace_id=traceID func=funcName caller=main.go:23 msg=message
+//
+// Errors are written under the "error" key and the name of a named Logger
+// under the "logger" key.
+type LogfmtEncoder struct{}
+
+// Encode implements the Encoder interface.
+func (LogfmtEncoder) Encode(e Entry) ([]byte, error) {
+	fs := []Field{
+		{Key: "time", Value: e.Time.Format(time.RFC3339Nano)},
+		{Key: "level", Value: LevelName(e.Level)},
+	}
+
+	if e.Name != "" {
+		fs = append(fs, Field{Key: "logger", Value: e.Name})
+	}
+
+	fs = append(fs,
+		Field{Key: "trace_id", Value: e.TraceID},
+		Field{Key: "func", Value: e.FuncName},
+		Field{Key: "caller", Value: e.Caller()},
+		Field{Key: "msg", Value: e.Message},
+	)
+
+	if e.Err != nil {
+		fs = append(fs, Err(e.Err))
+	}
+
+	fs = append(fs, e.Fields...)
+
+	return []byte(formatFields(fs) + "\n"), nil
+}
+
+// shortFile returns the final element of the file name.
+func shortFile(file string) string {
+	if idx := strings.LastIndex(file, "/"); idx != -1 {
+		return file[idx+1:]
+	}
+
+	return file
+}
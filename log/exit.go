@@ -0,0 +1,52 @@
+package log
+
+import (
+	"os"
+)
+
+// WithExit sets the function the Fatal calls use to terminate the app after
+// the exit hooks have run. The default is os.Exit. Tests can replace it to
+// check that a Fatal call happened without ending the test binary.
+func WithExit(exit func(code int)) Option {
+	return func(l *Logger) {
+		l.exit = exit
+	}
+}
+
+// OnExit registers a hook that is run by the Fatal calls before the app is
+// terminated, such as flushing an Async writer or stopping a tcp or udp
+// server. Hooks run in the reverse order they were registered, like deferred
+// calls. Loggers returned by Named and Limit register with the Logger they
+// were created from.
+func (l *Logger) OnExit(hook func()) {
+	r := l.root()
+
+	r.mu.Lock()
+	{
+		r.hooks = append(r.hooks, hook)
+	}
+	r.mu.Unlock()
+}
+
+// terminate writes the summary of the suppressed entries, runs the exit hooks
+// and calls the exit function with code 1.
+func (l *Logger) terminate() {
+	r := l.root()
+	r.summarize()
+
+	r.mu.RLock()
+	hooks := make([]func(), len(r.hooks))
+	copy(hooks, r.hooks)
+	exit := r.exit
+	r.mu.RUnlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+
+	if exit == nil {
+		exit = os.Exit
+	}
+
+	exit(1)
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is a key and typed value attached to a structured log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a Field holding a string value.
+func String(key string, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a Field holding an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns a Field holding an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a Field holding a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a Field holding a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns a Field holding a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a Field holding a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns a Field holding an error under the key "error".
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any returns a Field holding any value.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// badKey is the key used for a value that isn't preceded by a string key.
+const badKey = "!BADKEY"
+
+// fields converts a list of alternating keys and values to Fields. A Field
+// in the list is taken as is. A value without a string key is kept under
+// badKey so it isn't lost.
+func fields(keysAndValues []interface{}) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fs := make([]Field, 0, len(keysAndValues)/2+1)
+
+	for i := 0; i < len(keysAndValues); i++ {
+		switch kv := keysAndValues[i].(type) {
+		case Field:
+			fs = append(fs, kv)
+
+		case string:
+			if i+1 == len(keysAndValues) {
+				fs = append(fs, Field{Key: badKey, Value: kv})
+				continue
+			}
+
+			fs = append(fs, Field{Key: kv, Value: keysAndValues[i+1]})
+			i++
+
+		default:
+			fs = append(fs, Field{Key: badKey, Value: kv})
+		}
+	}
+
+	return fs
+}
+
+// formatFields returns the fields as space separated key=value pairs.
+func formatFields(fs []Field) string {
+	strs := make([]string, len(fs))
+	for i, f := range fs {
+		strs[i] = f.Key + "=" + quoteValue(formatValue(f.Value))
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// formatValue converts a field value to its string form.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprintf("%+v", v)
+}
+
+// quoteValue quotes the value when it's empty or contains spaces, quotes, an
+// equals sign or control characters.
+func quoteValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JournalConfig provides the settings for a Journal.
+type JournalConfig struct {
+	Path string // Path of the journal socket, defaults to /run/systemd/journal/socket.
+	Tag  string // Value of SYSLOG_IDENTIFIER, defaults to the program name.
+}
+
+// Journal sends entries to systemd-journald using its native protocol over
+// the unix datagram socket, so the trace ID, caller and fields of each entry
+// are kept as separate journal fields:
+//
+//	j, err := log.NewJournal(log.JournalConfig{})
+//	log.Init(j, logLevel, 0)
+//
+// Field keys are converted to journal field names by upper casing them and
+// replacing the characters that aren't allowed with an underscore. Entries
+// that don't fit in a datagram are not sent.
+type Journal struct {
+	cfg  JournalConfig
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewJournal connects to the journal socket.
+func NewJournal(cfg JournalConfig) (*Journal, error) {
+	if cfg.Path == "" {
+		cfg.Path = "/run/systemd/journal/socket"
+	}
+
+	if cfg.Tag == "" {
+		cfg.Tag = filepath.Base(os.Args[0])
+	}
+
+	conn, err := net.Dial("unixgram", cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	j := Journal{
+		cfg:  cfg,
+		conn: conn,
+	}
+
+	return &j, nil
+}
+
+// WriteEntry implements the EntryWriter interface.
+func (j *Journal) WriteEntry(e Entry) error {
+	var buf bytes.Buffer
+
+	journalField(&buf, "MESSAGE", e.Message)
+	journalField(&buf, "PRIORITY", strconv.Itoa(severity(e.Level)))
+	journalField(&buf, "SYSLOG_IDENTIFIER", j.cfg.Tag)
+	journalField(&buf, "LEVEL", LevelName(e.Level))
+
+	if e.Name != "" {
+		journalField(&buf, "LOGGER", e.Name)
+	}
+	if e.TraceID != "" {
+		journalField(&buf, "TRACE_ID", e.TraceID)
+	}
+	if e.FuncName != "" {
+		journalField(&buf, "CODE_FUNC", e.FuncName)
+	}
+	if e.File != "" {
+		journalField(&buf, "CODE_FILE", e.File)
+		journalField(&buf, "CODE_LINE", strconv.Itoa(e.Line))
+	}
+	if e.Err != nil {
+		journalField(&buf, "ERROR", e.Err.Error())
+	}
+
+	for _, f := range e.Fields {
+		journalField(&buf, journalName(f.Key), formatValue(f.Value))
+	}
+
+	return j.send(buf.Bytes())
+}
+
+// Write implements the io.Writer interface. Output that doesn't come from an
+// entry, such as from the embedded standard logger, is sent as the message
+// with the info priority.
+func (j *Journal) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+
+	journalField(&buf, "MESSAGE", strings.TrimRight(string(p), "\n"))
+	journalField(&buf, "PRIORITY", strconv.Itoa(severity(USER)))
+	journalField(&buf, "SYSLOG_IDENTIFIER", j.cfg.Tag)
+
+	if err := j.send(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to the journal socket.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.conn.Close()
+}
+
+// send sends the datagram, reconnecting once when journald was restarted.
+func (j *Journal) send(data []byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.conn.Write(data); err != nil {
+		conn, derr := net.Dial("unixgram", j.cfg.Path)
+		if derr != nil {
+			return err
+		}
+
+		j.conn.Close()
+		j.conn = conn
+
+		_, err = j.conn.Write(data)
+		return err
+	}
+
+	return nil
+}
+
+// journalField writes a field in the native protocol. Values holding a
+// newline are written as the name, a newline, the length as a little endian
+// 64 bit integer and the value.
+func journalField(buf *bytes.Buffer, name string, value string) {
+	buf.WriteString(name)
+
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalName converts a field key to a journal field name, which may only
+// hold upper case letters, digits and underscores and may not start with an
+// underscore or a digit.
+func journalName(key string) string {
+	b := []byte(strings.ToUpper(key))
+	for i, c := range b {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+
+	name := strings.TrimLeft(string(b), "_0123456789")
+	if name == "" {
+		return "FIELD"
+	}
+
+	return name
+}
@@ -4,17 +4,108 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
-// Level constants that define the supported usable LogLevel.
+// Level constants that define the supported usable LogLevel. Levels are
+// ordered from the most verbose, TRACE, to the most severe, PANIC. A level
+// handler returning a level enables logging at that level and every level
+// above it, while NONE disables logging. The values of NONE, DEV and USER are
+// kept from the original levels, so TRACE is -1 and sorts below NONE. NONE is
+// not part of the ordering: check for it with == rather than comparing levels
+// with < or >.
 const (
-	NONE int = iota
+	TRACE int = iota - 1
+	NONE
 	DEV
 	USER
+	WARN
+	ERROR
+	FATAL
+	PANIC
 )
 
+// DEBUG and INFO are the conventional names for the DEV and USER levels.
+const (
+	DEBUG = DEV
+	INFO  = USER
+)
+
+// levelNames maps the levels to the names used when they are logged.
+var levelNames = map[int]string{
+	TRACE: "TRACE",
+	NONE:  "NONE",
+	DEV:   "DEV",
+	USER:  "USER",
+	WARN:  "WARN",
+	ERROR: "ERROR",
+	FATAL: "FATAL",
+	PANIC: "PANIC",
+}
+
+// LevelName returns the name of the level as it is logged.
+func LevelName(level int) string {
+	if name, found := levelNames[level]; found {
+		return name
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", level)
+}
+
+// ParseLevel converts a level name, ignoring case, or its number to a level.
+// DEBUG and INFO are accepted for DEV and USER and -1 is accepted for TRACE.
+// NONE is returned along with the error for an unknown level, since TRACE
+// sorts below NONE the error must be checked rather than the level compared.
+func ParseLevel(s string) (int, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	switch s {
+	case "DEBUG":
+		return DEV, nil
+	case "INFO":
+		return USER, nil
+	}
+
+	for level, name := range levelNames {
+		if name == s {
+			return level, nil
+		}
+	}
+
+	if level, err := strconv.Atoi(s); err == nil {
+		if _, found := levelNames[level]; found {
+			return level, nil
+		}
+	}
+
+	return NONE, fmt.Errorf("unknown level %q", s)
+}
+
+// LevelFrom returns a level handler that reads the level from a configuration
+// key on every call, using def when the key is not found or doesn't hold a
+// valid level. The lookup function matches cfg.String:
+//
+//	lg.Named("tcp").SetLevel(log.LevelFrom(cfg.String, "LOG_LEVEL_TCP", log.USER))
+func LevelFrom(lookup func(key string) (string, error), key string, def int) func() int {
+	return func() int {
+		s, err := lookup(key)
+		if err != nil {
+			return def
+		}
+
+		level, err := ParseLevel(s)
+		if err != nil {
+			return def
+		}
+
+		return level
+	}
+}
+
 const (
 	// Ldate enables the date in the local time zone: 2009/01/23
 	Ldate = 1 << iota
@@ -34,148 +125,346 @@ const (
 	Ldefault = log.Ldate | log.Ltime | log.Lshortfile
 )
 
-// Logger contains a standard logger for all logging.
+// Logger contains a standard logger for all logging. A Logger returned by
+// Named shares its output with the Logger it was created from. The methods of
+// the standard logger, such as Printf, write their output as is whatever the
+// level, through the same output and write lock as the entries.
 type Logger struct {
 	*log.Logger
 	level func() int
 	mu    sync.RWMutex
+
+	out    io.Writer
+	enc    Encoder
+	wmu    sync.Mutex
+	levels map[string]func() int
+	smp    *sampler
+	exit   func(code int)
+	hooks  []func()
+
+	// A named Logger created with Named or Limit writes through parent.
+	parent *Logger
+	name   string
+	limit  *limit
+}
+
+// Option configures a Logger created by New or Init.
+type Option func(*Logger)
+
+// WithEncoder sets the Encoder used to format each entry. The default is a
+// TextEncoder using the flags given to New or Init.
+func WithEncoder(enc Encoder) Option {
+	return func(l *Logger) {
+		l.enc = enc
+	}
 }
 
 // New returns a instance of a logger.
-func New(w io.Writer, levelHandler func() int, flags int) *Logger {
-	return &Logger{
-		Logger: log.New(w, "", flags),
-		level:  levelHandler,
+func New(w io.Writer, levelHandler func() int, flags int, opts ...Option) *Logger {
+	l := Logger{
+		level: levelHandler,
+		out:   w,
+		enc:   TextEncoder{Flags: flags},
+		smp:   &sampler{},
 	}
+	l.Logger = log.New(stdWriter{&l}, "", flags)
+
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	return &l
 }
 
-// mLevel sets the default log level for use with the log methods.
-const mLevel = 2
+// Named returns a child Logger whose entries carry the given name, joined to
+// the name of this Logger with a dot. The child writes to the same output and
+// uses the level of this Logger until it is given its own with SetLevel.
+func (l *Logger) Named(name string) *Logger {
+	r := l.root()
 
-// Dev logs trace information for developers.
-func (l *Logger) Dev(traceID string, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	return &Logger{Logger: r.Logger, parent: r, name: name}
+}
+
+// SetLevel replaces the level handler. On a Logger returned by Named it
+// overrides the level for that name and the names below it. On a Logger
+// returned by Limit it sets the level of the name it shares and has no effect
+// when that name is empty.
+func (l *Logger) SetLevel(levelHandler func() int) {
+	r := l.root()
+
+	r.mu.Lock()
 	{
-		if l.level() == DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
+		if l.parent == nil {
+			r.level = levelHandler
+		} else if l.name != "" {
+			if r.levels == nil {
+				r.levels = make(map[string]func() int)
 			}
-
-			l.Output(mLevel, fmt.Sprintf("DEV : %s : %s : %s", traceID, funcName, format))
+			r.levels[l.name] = levelHandler
 		}
 	}
-	l.mu.RUnlock()
+	r.mu.Unlock()
+}
+
+// root returns the Logger that holds the output.
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+
+	return l
+}
+
+// mLevel sets the default log level for use with the log methods.
+const mLevel = 2
+
+// Trace logs detailed trace information for developers.
+func (l *Logger) Trace(traceID string, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel, TRACE, traceID, funcName, nil, format, a, nil)
+}
+
+// Dev logs trace information for developers.
+func (l *Logger) Dev(traceID string, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel, DEV, traceID, funcName, nil, format, a, nil)
 }
 
 // User logs trace information for users.
 func (l *Logger) User(traceID string, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
+	l.logf(mLevel, USER, traceID, funcName, nil, format, a, nil)
+}
 
-			l.Output(mLevel, fmt.Sprintf("USER : %s : %s : %s", traceID, funcName, format))
-		}
-	}
-	l.mu.RUnlock()
+// Warn logs trace information for users about unexpected situations that are
+// not errors.
+func (l *Logger) Warn(traceID string, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel, WARN, traceID, funcName, nil, format, a, nil)
 }
 
 // Error logs trace information that are errors.
 func (l *Logger) Error(traceID string, funcName string, err error, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
-
-			l.Output(mLevel, fmt.Sprintf("ERROR : %s : %s : %+v : %s", traceID, funcName, err, format))
-		}
-	}
-	l.mu.RUnlock()
+	l.logf(mLevel, ERROR, traceID, funcName, err, format, a, nil)
 }
 
-// Fatal logs trace information for users and terminates the app.
+// Fatal logs trace information for users, runs the exit hooks and terminates
+// the app.
 func (l *Logger) Fatal(traceID string, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
+	l.logf(mLevel, FATAL, traceID, funcName, nil, format, a, nil)
 
-			l.Output(mLevel, fmt.Sprintf("FATAL : %s : %s : %s", traceID, funcName, format))
-		}
-	}
-	l.mu.RUnlock()
+	l.terminate()
+}
+
+// Panic logs trace information for users and panics with the message.
+func (l *Logger) Panic(traceID string, funcName string, format string, a ...interface{}) {
+	msg := sprintf(format, a)
+	l.logf(mLevel, PANIC, traceID, funcName, nil, msg, nil, nil)
 
-	os.Exit(1)
+	panic(msg)
+}
+
+// TraceOffset logs detailed trace information for developers with a offset
+// option to expand the caller level.
+func (l *Logger) TraceOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel+offset, TRACE, traceID, funcName, nil, format, a, nil)
 }
 
 // DevOffset logs trace information for developers with a offset option to
 // expand the caller level.
 func (l *Logger) DevOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() == DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
-
-			l.Output(mLevel+offset, fmt.Sprintf("DEV : %s : %s : %s", traceID, funcName, format))
-		}
-	}
-	l.mu.RUnlock()
+	l.logf(mLevel+offset, DEV, traceID, funcName, nil, format, a, nil)
 }
 
 // UserOffset logs trace information for users with a offset option to expand the
 // caller level.
 func (l *Logger) UserOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
+	l.logf(mLevel+offset, USER, traceID, funcName, nil, format, a, nil)
+}
 
-			l.Output(mLevel+offset, fmt.Sprintf("USER : %s : %s : %s", traceID, funcName, format))
-		}
-	}
-	l.mu.RUnlock()
+// WarnOffset logs trace information for users about unexpected situations
+// with a offset option to expand the caller level.
+func (l *Logger) WarnOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel+offset, WARN, traceID, funcName, nil, format, a, nil)
 }
 
 // ErrorOffset logs trace information that are errors with a offset option to
 // expand the caller level.
 func (l *Logger) ErrorOffset(traceID string, offset int, funcName string, err error, format string, a ...interface{}) {
-	l.mu.RLock()
-	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
+	l.logf(mLevel+offset, ERROR, traceID, funcName, err, format, a, nil)
+}
+
+// FatalOffset logs trace information for users, runs the exit hooks and
+// terminates the app with a offset expand the caller level.
+func (l *Logger) FatalOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.logf(mLevel+offset, FATAL, traceID, funcName, nil, format, a, nil)
+
+	l.terminate()
+}
+
+// PanicOffset logs trace information for users and panics with the message
+// with a offset option to expand the caller level.
+func (l *Logger) PanicOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	msg := sprintf(format, a)
+	l.logf(mLevel+offset, PANIC, traceID, funcName, nil, msg, nil, nil)
+
+	panic(msg)
+}
+
+// Tracew logs a structured message with detailed trace information for
+// developers. The message is followed by a list of alternating keys and
+// values, or Fields, that are appended to the log line as key=value pairs.
+func (l *Logger) Tracew(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, TRACE, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Devw logs a structured message for developers. The message is followed by
+// a list of alternating keys and values, or Fields, that are appended to the
+// log line as key=value pairs.
+func (l *Logger) Devw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, DEV, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Userw logs a structured message for users. The message is followed by a
+// list of alternating keys and values, or Fields, that are appended to the log
+// line as key=value pairs.
+func (l *Logger) Userw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, USER, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Warnw logs a structured message for users about unexpected situations. The
+// message is followed by a list of alternating keys and values, or Fields,
+// that are appended to the log line as key=value pairs.
+func (l *Logger) Warnw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, WARN, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Errorw logs a structured message that is an error. The message is followed
+// by a list of alternating keys and values, or Fields, that are appended to
+// the log line as key=value pairs.
+func (l *Logger) Errorw(traceID string, funcName string, err error, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, ERROR, traceID, funcName, err, msg, keysAndValues)
+}
+
+// Fatalw logs a structured message for users, runs the exit hooks and
+// terminates the app. The message is followed by a list of alternating keys
+// and values, or Fields, that are appended to the log line as key=value pairs.
+func (l *Logger) Fatalw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, FATAL, traceID, funcName, nil, msg, keysAndValues)
+
+	l.terminate()
+}
+
+// Panicw logs a structured message for users and panics with the message.
+// The message is followed by a list of alternating keys and values, or
+// Fields, that are appended to the log line as key=value pairs.
+func (l *Logger) Panicw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, PANIC, traceID, funcName, nil, msg, keysAndValues)
+
+	panic(msg)
+}
+
+// sprintf formats the message only when arguments were given, so a message
+// without arguments is kept as is even when it contains a %.
+func sprintf(format string, a []interface{}) string {
+	if a == nil {
+		return format
+	}
+
+	return fmt.Sprintf(format, a...)
+}
+
+// logf writes a printf style message with any fields when the level is
+// enabled.
+func (l *Logger) logf(calldepth int, level int, traceID string, funcName string, err error, format string, a []interface{}, fs []Field) {
+	r := l.root()
 
-			l.Output(mLevel+offset, fmt.Sprintf("ERROR : %s : %s : %+v : %s", traceID, funcName, err, format))
+	r.mu.RLock()
+	{
+		if r.enabled(l.name, level) && r.allow(calldepth+1, l.limit) {
+			r.write(calldepth+1, Entry{Level: level, Name: l.name, TraceID: traceID, FuncName: funcName, Message: sprintf(format, a), Err: err, Fields: fs})
 		}
 	}
-	l.mu.RUnlock()
+	r.mu.RUnlock()
 }
 
-// FatalOffset logs trace information for users and terminates the app with a
-// offset expand the caller level.
-func (l *Logger) FatalOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
-	l.mu.RLock()
+// logw writes a structured message when the level is enabled.
+func (l *Logger) logw(calldepth int, level int, traceID string, funcName string, err error, msg string, keysAndValues []interface{}) {
+	r := l.root()
+
+	r.mu.RLock()
 	{
-		if l.level() >= DEV {
-			if a != nil {
-				format = fmt.Sprintf(format, a...)
-			}
+		if r.enabled(l.name, level) && r.allow(calldepth+1, l.limit) {
+			r.write(calldepth+1, Entry{Level: level, Name: l.name, TraceID: traceID, FuncName: funcName, Message: msg, Err: err, Fields: fields(keysAndValues)})
+		}
+	}
+	r.mu.RUnlock()
+}
+
+// enabled reports whether the level is logged for the given name. The level
+// set for the longest matching name is used, falling back to the Logger's
+// level. The caller must hold the read lock.
+func (l *Logger) enabled(name string, level int) bool {
+	handler := l.level
+
+	for name != "" {
+		if h, found := l.levels[name]; found {
+			handler = h
+			break
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx == -1 {
+			break
+		}
+		name = name[:idx]
+	}
+
+	if handler == nil {
+		return false
+	}
+
+	min := handler()
+	return min != NONE && level >= min
+}
+
+// write completes the entry with the time and the caller at the given depth,
+// encodes it and writes it to the output, or passes it to an output that is
+// an EntryWriter. When the entry has no function name the name of the caller
+// is used. The caller must hold the read lock.
+func (l *Logger) write(calldepth int, e Entry) {
+	e.Time = time.Now()
 
-			l.Output(mLevel+offset, fmt.Sprintf("FATAL : %s : %s : %s", traceID, funcName, format))
+	pc, file, line, ok := runtime.Caller(calldepth)
+	if !ok {
+		file = "???"
+	}
+	e.File, e.Line = file, line
+
+	if e.FuncName == "" {
+		e.FuncName = "???"
+		if ok {
+			e.FuncName = funcName(pc)
+		}
+	}
+
+	if ew, ok := l.out.(EntryWriter); ok && acceptsEntries(l.out) {
+		l.wmu.Lock()
+		{
+			ew.WriteEntry(e)
 		}
+		l.wmu.Unlock()
+		return
 	}
-	l.mu.RUnlock()
 
-	os.Exit(1)
+	data, err := l.enc.Encode(e)
+	if err != nil {
+		return
+	}
+
+	l.wmu.Lock()
+	{
+		l.out.Write(data)
+	}
+	l.wmu.Unlock()
 }
@@ -0,0 +1,149 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// blockedWriter blocks every write until it is released.
+type blockedWriter struct {
+	entered chan struct{}
+	release chan struct{}
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements the io.Writer interface.
+func (bw *blockedWriter) Write(p []byte) (int, error) {
+	select {
+	case bw.entered <- struct{}{}:
+	default:
+	}
+	<-bw.release
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.buf.Write(p)
+}
+
+// String returns everything written.
+func (bw *blockedWriter) String() string {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.buf.String()
+}
+
+// TestAsync tests the async writer and its policies when the buffer is full.
+func TestAsync(t *testing.T) {
+	t.Log("Given the need to write log entries asynchronously.")
+	{
+		tests := []struct {
+			policy  log.Policy
+			name    string
+			exp     string
+			dropped uint64
+		}{
+			{log.DropNewest, "DropNewest", "1\n2\n", 2},
+			{log.DropOldest, "DropOldest", "3\n4\n", 2},
+		}
+
+		for _, tt := range tests {
+			t.Logf("\tWhen the buffer is full with the %s policy.", tt.name)
+			{
+				bw := blockedWriter{entered: make(chan struct{}, 1), release: make(chan struct{})}
+				a := log.NewAsync(&bw, log.AsyncConfig{Size: 2, Policy: tt.policy, FlushInterval: time.Hour})
+
+				// An entry larger than the output buffer is written straight
+				// through, which blocks the goroutine in the writer.
+				big := strings.Repeat("x", 8192) + "\n"
+				a.Write([]byte(big))
+				<-bw.entered
+
+				for _, s := range []string{"1\n", "2\n", "3\n", "4\n"} {
+					a.Write([]byte(s))
+				}
+
+				close(bw.release)
+				if err := a.Close(); err != nil {
+					t.Fatalf("\t\t%v : Should close without error : %v", Failed, err)
+				}
+
+				if got := strings.TrimPrefix(bw.String(), big); got == tt.exp {
+					t.Logf("\t\t%v : Should keep the expected entries.", Success)
+				} else {
+					t.Log("***>", got)
+					t.Log("***>", tt.exp)
+					t.Errorf("\t\t%v : Should keep the expected entries.", Failed)
+				}
+
+				if a.Dropped() == tt.dropped {
+					t.Logf("\t\t%v : Should count the dropped entries.", Success)
+				} else {
+					t.Errorf("\t\t%v : Should count the dropped entries : got %d", Failed, a.Dropped())
+				}
+
+				if _, err := a.Write([]byte("5\n")); err != log.ErrClosed {
+					t.Errorf("\t\t%v : Should not accept entries once closed : %v", Failed, err)
+				} else {
+					t.Logf("\t\t%v : Should not accept entries once closed.", Success)
+				}
+			}
+		}
+
+		t.Log("\tWhen logging through an async writer.")
+		{
+			var buf bytes.Buffer
+			a := log.NewAsync(&buf, log.AsyncConfig{FlushInterval: time.Hour})
+			defer a.Close()
+
+			lg := log.New(a, func() int { return log.DEV }, 0)
+			for i := 0; i < 100; i++ {
+				lg.Dev("traceID", "FuncName", "Message %d", i)
+			}
+
+			if err := a.Flush(); err != nil {
+				t.Fatalf("\t\t%v : Should flush without error : %v", Failed, err)
+			}
+
+			if n := strings.Count(buf.String(), "\n"); n == 100 && strings.HasSuffix(buf.String(), "Message 99\n") {
+				t.Logf("\t\t%v : Should write every entry in order when flushed.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should write every entry in order when flushed : got %d lines", Failed, n)
+			}
+		}
+
+		t.Log("\tWhen logging through an async writer to a Multi.")
+		{
+			var users, devs bytes.Buffer
+			m := log.NewMulti(
+				log.Sink{Writer: &users, Level: func() int { return log.USER }},
+				log.Sink{Writer: &devs},
+			)
+
+			a := log.NewAsync(m, log.AsyncConfig{FlushInterval: time.Hour})
+			defer a.Close()
+
+			lg := log.New(a, func() int { return log.DEV }, 0)
+			lg.Dev("traceID", "FuncName", "Message 1")
+			lg.User("traceID", "FuncName", "Message 2")
+
+			if err := a.Flush(); err != nil {
+				t.Fatalf("\t\t%v : Should flush without error : %v", Failed, err)
+			}
+
+			if strings.Count(users.String(), "\n") == 1 && strings.Count(devs.String(), "\n") == 2 {
+				t.Logf("\t\t%v : Should pass the entries on to the sinks by level.", Success)
+			} else {
+				t.Log("***>", users.String())
+				t.Log("***>", devs.String())
+				t.Errorf("\t\t%v : Should pass the entries on to the sinks by level.", Failed)
+			}
+		}
+	}
+}
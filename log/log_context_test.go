@@ -0,0 +1,65 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestContext tests logging with the values held by a context.
+func TestContext(t *testing.T) {
+	t.Log("Given the need to log with the values from a context.")
+	{
+		var buf bytes.Buffer
+		lg := log.New(&buf, func() int { return log.DEV }, 0)
+
+		ctx := log.WithTraceID(context.Background(), "traceID")
+		ctx = log.WithFields(ctx, "user", "bill")
+		ctx = log.WithFields(ctx, log.Int("attempt", 2))
+
+		t.Log("\tWhen we log with a context.")
+		{
+			lg.DevCtx(ctx, "Message %d", 1)
+			lg.ErrorCtx(ctx, errors.New("An error"), "Failed")
+
+			exp := "DEV : traceID : TestContext : Message 1 : user=bill attempt=2\n" +
+				"ERROR : traceID : TestContext : An error : Failed : user=bill attempt=2\n"
+
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log the trace ID, fields and function name.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log the trace ID, fields and function name.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the context holds a Logger.")
+		{
+			buf.Reset()
+
+			var w worker
+			w.run(log.WithContext(ctx, lg.Named("worker")))
+
+			exp := "[worker] USER : traceID : worker.run : Running : user=bill attempt=2\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log with the Logger from the context.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log with the Logger from the context.", Failed)
+			}
+		}
+	}
+}
+
+// worker provides a method to test the inferred function name.
+type worker struct{}
+
+// run logs with the package level functions.
+func (w *worker) run(ctx context.Context) {
+	log.UserCtx(ctx, "Running")
+}
@@ -1,23 +1,54 @@
 package log
 
-import "io"
+import (
+	"io"
+	"log"
+	"time"
+)
 
 // l defines the default log variable for the global log functions.
 var l Logger
 
 // Init initializes the default logger to allow usage of the global log
 // functions.
-func Init(w io.Writer, level func() int, flags int) {
+func Init(w io.Writer, level func() int, flags int, opts ...Option) {
 	l.mu.Lock()
 	{
-		dl := New(w, level, flags)
+		dl := New(w, level, flags, opts...)
 
-		l.Logger = dl.Logger
+		l.Logger = log.New(stdWriter{&l}, "", flags)
 		l.level = dl.level
+		l.out = dl.out
+		l.enc = dl.enc
+		l.smp = dl.smp
+		l.exit = dl.exit
 	}
 	l.mu.Unlock()
 }
 
+// Named returns a child of the default logger whose entries carry the given
+// name and whose level can be set independently with SetLevel.
+func Named(name string) *Logger {
+	return l.Named(name)
+}
+
+// OnExit registers a hook with the default logger that is run by the Fatal
+// calls before the app is terminated.
+func OnExit(hook func()) {
+	l.OnExit(hook)
+}
+
+// Limit returns a Logger that writes at most n entries per period for the
+// key with the default logger and suppresses the rest.
+func Limit(key string, n int, per time.Duration) *Logger {
+	return l.Limit(key, n, per)
+}
+
+// Trace logs detailed trace information for developers.
+func Trace(traceID string, funcName string, format string, a ...interface{}) {
+	l.TraceOffset(traceID, 1, funcName, format, a...)
+}
+
 // Dev logs trace information for developers.
 func Dev(traceID string, funcName string, format string, a ...interface{}) {
 	l.DevOffset(traceID, 1, funcName, format, a...)
@@ -28,16 +59,34 @@ func User(traceID string, funcName string, format string, a ...interface{}) {
 	l.UserOffset(traceID, 1, funcName, format, a...)
 }
 
+// Warn logs trace information for users about unexpected situations that are
+// not errors.
+func Warn(traceID string, funcName string, format string, a ...interface{}) {
+	l.WarnOffset(traceID, 1, funcName, format, a...)
+}
+
 // Error logs trace information that are errors.
 func Error(traceID string, funcName string, err error, format string, a ...interface{}) {
 	l.ErrorOffset(traceID, 1, funcName, err, format, a...)
 }
 
-// Fatal logs trace information for users and terminates the app.
+// Fatal logs trace information for users, runs the exit hooks and terminates
+// the app.
 func Fatal(traceID string, funcName string, format string, a ...interface{}) {
 	l.FatalOffset(traceID, 1, funcName, format, a...)
 }
 
+// Panic logs trace information for users and panics with the message.
+func Panic(traceID string, funcName string, format string, a ...interface{}) {
+	l.PanicOffset(traceID, 1, funcName, format, a...)
+}
+
+// TraceOffset logs detailed trace information for developers with a offset
+// option to expand the caller level.
+func TraceOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.TraceOffset(traceID, offset+1, funcName, format, a...)
+}
+
 // DevOffset logs trace information for developers with a offset option to
 // expand the caller level.
 func DevOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
@@ -50,14 +99,72 @@ func UserOffset(traceID string, offset int, funcName string, format string, a ..
 	l.UserOffset(traceID, offset+1, funcName, format, a...)
 }
 
+// WarnOffset logs trace information for users about unexpected situations
+// with a offset option to expand the caller level.
+func WarnOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.WarnOffset(traceID, offset+1, funcName, format, a...)
+}
+
 // ErrorOffset logs trace information that are errors with a offset option to
 // expand the caller level.
 func ErrorOffset(traceID string, offset int, funcName string, err error, format string, a ...interface{}) {
 	l.ErrorOffset(traceID, offset+1, funcName, err, format, a...)
 }
 
-// FatalOffset logs trace information for users and terminates the app with a
-// offset expand the caller level.
+// FatalOffset logs trace information for users, runs the exit hooks and
+// terminates the app with a offset expand the caller level.
 func FatalOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
 	l.FatalOffset(traceID, offset+1, funcName, format, a...)
 }
+
+// PanicOffset logs trace information for users and panics with the message
+// with a offset option to expand the caller level.
+func PanicOffset(traceID string, offset int, funcName string, format string, a ...interface{}) {
+	l.PanicOffset(traceID, offset+1, funcName, format, a...)
+}
+
+// Tracew logs a structured message with detailed trace information for
+// developers with a list of alternating keys and values, or Fields.
+func Tracew(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, TRACE, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Devw logs a structured message for developers with a list of alternating
+// keys and values, or Fields.
+func Devw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, DEV, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Userw logs a structured message for users with a list of alternating keys
+// and values, or Fields.
+func Userw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, USER, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Warnw logs a structured message for users about unexpected situations with
+// a list of alternating keys and values, or Fields.
+func Warnw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, WARN, traceID, funcName, nil, msg, keysAndValues)
+}
+
+// Errorw logs a structured message that is an error with a list of
+// alternating keys and values, or Fields.
+func Errorw(traceID string, funcName string, err error, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, ERROR, traceID, funcName, err, msg, keysAndValues)
+}
+
+// Fatalw logs a structured message for users with a list of alternating keys
+// and values, or Fields, runs the exit hooks and terminates the app.
+func Fatalw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, FATAL, traceID, funcName, nil, msg, keysAndValues)
+
+	l.terminate()
+}
+
+// Panicw logs a structured message for users with a list of alternating keys
+// and values, or Fields, and panics with the message.
+func Panicw(traceID string, funcName string, msg string, keysAndValues ...interface{}) {
+	l.logw(mLevel, PANIC, traceID, funcName, nil, msg, keysAndValues)
+
+	panic(msg)
+}
@@ -0,0 +1,92 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestEncoders tests formatting entries with the different encoders.
+func TestEncoders(t *testing.T) {
+	t.Log("Given the need to format log entries.")
+	{
+		e := log.Entry{
+			Time:     time.Date(2009, 1, 23, 1, 23, 23, 123456000, time.UTC),
+			Level:    log.ERROR,
+			TraceID:  "traceID",
+			FuncName: "FuncName",
+			File:     "/src/app/main.go",
+			Line:     23,
+			Message:  "Failed to connect",
+			Err:      errors.New("An error"),
+			Fields:   []log.Field{log.String("host", "db 1"), log.Int("port", 5432), log.Duration("took", time.Second)},
+		}
+
+		t.Log("\tWhen we use the text encoder.")
+		{
+			data, _ := log.TextEncoder{Flags: log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile}.Encode(e)
+
+			exp := "2009/01/23 01:23:23.123456 main.go:23: ERROR : traceID : FuncName : An error : Failed to connect : host=\"db 1\" port=5432 took=1s\n"
+			if string(data) == exp {
+				t.Logf("\t\t%v : Should format the entry in the kit format.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should format the entry in the kit format.", Failed)
+			}
+		}
+
+		t.Log("\tWhen we use the JSON encoder.")
+		{
+			data, _ := log.JSONEncoder{}.Encode(e)
+
+			exp := `{"time":"2009-01-23T01:23:23.123456Z","level":"ERROR","trace_id":"traceID","func":"FuncName","caller":"main.go:23","msg":"Failed to connect","error":"An error","host":"db 1","port":5432,"took":"1s"}` + "\n"
+			if string(data) == exp {
+				t.Logf("\t\t%v : Should format the entry as a JSON object.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should format the entry as a JSON object.", Failed)
+			}
+		}
+
+		t.Log("\tWhen we use the logfmt encoder.")
+		{
+			data, _ := log.LogfmtEncoder{}.Encode(e)
+
+			exp := `time=2009-01-23T01:23:23.123456Z level=ERROR trace_id=traceID func=FuncName caller=main.go:23 msg="Failed to connect" error="An error" host="db 1" port=5432 took=1s` + "\n"
+			if string(data) == exp {
+				t.Logf("\t\t%v : Should format the entry as logfmt.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should format the entry as logfmt.", Failed)
+			}
+		}
+
+		t.Log("\tWhen we select an encoder for a logger.")
+		{
+			var buf bytes.Buffer
+			lg := log.New(&buf, func() int { return log.DEV }, log.Ldefault, log.WithEncoder(log.JSONEncoder{}))
+
+			lg.User("traceID", "FuncName", "Message %d", 1)
+
+			var m map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatalf("\t\t%v : Should write JSON : %v", Failed, err)
+			}
+
+			if m["msg"] == "Message 1" && m["level"] == "USER" && strings.HasPrefix(m["caller"].(string), "log_encoder_test.go:") {
+				t.Logf("\t\t%v : Should write entries with the encoder.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Errorf("\t\t%v : Should write entries with the encoder.", Failed)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestFatal tests the exit hooks and exit function used by Fatal.
+func TestFatal(t *testing.T) {
+	t.Log("Given the need to terminate the app after a fatal error.")
+	{
+		var buf bytes.Buffer
+		var calls []string
+
+		exit := func(code int) {
+			calls = append(calls, "exit")
+			if code != 1 {
+				t.Errorf("\t\t%v : Should exit with code 1 : got %d", Failed, code)
+			}
+		}
+
+		lg := log.New(&buf, func() int { return log.USER }, 0, log.WithExit(exit))
+		lg.OnExit(func() { calls = append(calls, "flush") })
+		lg.Named("tcp").OnExit(func() { calls = append(calls, "stop") })
+
+		t.Log("\tWhen we log a fatal message.")
+		{
+			lg.Fatal("traceID", "FuncName", "Message %d", 1)
+
+			exp := "FATAL : traceID : FuncName : Message 1\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log the message.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log the message.", Failed)
+			}
+
+			got := ""
+			for _, c := range calls {
+				got += c + " "
+			}
+
+			if got == "stop flush exit " {
+				t.Logf("\t\t%v : Should run the hooks in reverse order before exiting.", Success)
+			} else {
+				t.Log("***>", got)
+				t.Errorf("\t\t%v : Should run the hooks in reverse order before exiting.", Failed)
+			}
+		}
+	}
+}
+
+// TestPanic tests logging a message and panicking with it.
+func TestPanic(t *testing.T) {
+	t.Log("Given the need to panic after logging a message.")
+	{
+		var buf bytes.Buffer
+		lg := log.New(&buf, func() int { return log.USER }, 0)
+
+		t.Log("\tWhen we log a panic message.")
+		{
+			var recovered interface{}
+			func() {
+				defer func() { recovered = recover() }()
+				lg.Panic("traceID", "FuncName", "Message %d", 1)
+			}()
+
+			if recovered == "Message 1" {
+				t.Logf("\t\t%v : Should panic with the message.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should panic with the message : got %v", Failed, recovered)
+			}
+
+			exp := "PANIC : traceID : FuncName : Message 1\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log the message.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log the message.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the panic message has a literal % and no arguments.")
+		{
+			buf.Reset()
+
+			var recovered interface{}
+			func() {
+				defer func() { recovered = recover() }()
+				lg.Panic("traceID", "FuncName", "100%")
+			}()
+
+			if recovered == "100%" {
+				t.Logf("\t\t%v : Should panic with the message as is.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should panic with the message as is : got %v", Failed, recovered)
+			}
+
+			exp := "PANIC : traceID : FuncName : 100%\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log the message as is.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log the message as is.", Failed)
+			}
+
+			ctx := log.WithContext(context.Background(), lg)
+
+			func() {
+				defer func() { recovered = recover() }()
+				log.PanicCtx(ctx, "100%")
+			}()
+
+			if recovered == "100%" {
+				t.Logf("\t\t%v : Should panic with the context message as is.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should panic with the context message as is : got %v", Failed, recovered)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestLevels tests that each level only logs the levels above it.
+func TestLevels(t *testing.T) {
+	t.Log("Given the need to filter messages by level.")
+	{
+		tests := []struct {
+			level int
+			exp   string
+		}{
+			{log.NONE, ""},
+			{log.TRACE, "TRACE DEV USER WARN ERROR "},
+			{log.DEV, "DEV USER WARN ERROR "},
+			{log.USER, "USER WARN ERROR "},
+			{log.WARN, "WARN ERROR "},
+			{log.ERROR, "ERROR "},
+			{log.FATAL, ""},
+		}
+
+		for _, tt := range tests {
+			t.Logf("\tWhen we set the logging level to %s.", log.LevelName(tt.level))
+			{
+				var buf bytes.Buffer
+				lg := log.New(&buf, func() int { return tt.level }, 0, log.WithEncoder(levelEncoder{}))
+
+				lg.Trace("traceID", "FuncName", "Message")
+				lg.Dev("traceID", "FuncName", "Message")
+				lg.User("traceID", "FuncName", "Message")
+				lg.Warn("traceID", "FuncName", "Message")
+				lg.Error("traceID", "FuncName", errors.New("An error"), "Message")
+
+				if buf.String() == tt.exp {
+					t.Logf("\t\t%v : Should log the expected levels.", Success)
+				} else {
+					t.Log("***>", buf.String())
+					t.Log("***>", tt.exp)
+					t.Errorf("\t\t%v : Should log the expected levels.", Failed)
+				}
+			}
+		}
+	}
+}
+
+// TestNamed tests named loggers with their own level.
+func TestNamed(t *testing.T) {
+	t.Log("Given the need to log for a subsystem.")
+	{
+		values := map[string]string{"LOG_LEVEL_TCP": "warn"}
+		lookup := func(key string) (string, error) {
+			v, found := values[key]
+			if !found {
+				return "", errors.New("unknown key")
+			}
+			return v, nil
+		}
+
+		var buf bytes.Buffer
+		lg := log.New(&buf, func() int { return log.USER }, 0)
+
+		tcp := lg.Named("tcp")
+		tcp.SetLevel(log.LevelFrom(lookup, "LOG_LEVEL_TCP", log.DEV))
+		conn := tcp.Named("conn")
+
+		t.Log("\tWhen the named logger has its own level.")
+		{
+			tcp.User("traceID", "Accept", "Dropped")
+			conn.User("traceID", "Read", "Dropped")
+			conn.Warn("traceID", "Read", "Slow client")
+			lg.User("traceID", "main", "Started")
+
+			exp := "[tcp.conn] WARN : traceID : Read : Slow client\nUSER : traceID : main : Started\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should use the level of the closest name.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should use the level of the closest name.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the configured level changes.")
+		{
+			buf.Reset()
+			values["LOG_LEVEL_TCP"] = "1"
+
+			conn.Dev("traceID", "Read", "Message")
+
+			exp := "[tcp.conn] DEV : traceID : Read : Message\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should read the level on every call.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should read the level on every call.", Failed)
+			}
+		}
+	}
+}
+
+// TestParseLevel tests converting names and numbers to levels.
+func TestParseLevel(t *testing.T) {
+	t.Log("Given the need to read a level from configuration.")
+	{
+		tests := []struct {
+			s     string
+			level int
+		}{
+			{"trace", log.TRACE},
+			{"-1", log.TRACE},
+			{"NONE", log.NONE},
+			{"0", log.NONE},
+			{"debug", log.DEV},
+			{" Info ", log.USER},
+			{"6", log.PANIC},
+		}
+
+		t.Log("\tWhen we parse valid levels.")
+		{
+			for _, tt := range tests {
+				level, err := log.ParseLevel(tt.s)
+				if err != nil || level != tt.level {
+					t.Errorf("\t\t%v : Should parse %q as %s : got %d %v", Failed, tt.s, log.LevelName(tt.level), level, err)
+					continue
+				}
+				t.Logf("\t\t%v : Should parse %q as %s.", Success, tt.s, log.LevelName(tt.level))
+			}
+		}
+
+		t.Log("\tWhen we parse an unknown level.")
+		{
+			for _, s := range []string{"-2", "1abc"} {
+				if level, err := log.ParseLevel(s); err != nil && level == log.NONE {
+					t.Logf("\t\t%v : Should return NONE with an error for %q.", Success, s)
+				} else {
+					t.Errorf("\t\t%v : Should return NONE with an error for %q : got %d %v", Failed, s, level, err)
+				}
+			}
+		}
+	}
+}
+
+// levelEncoder writes only the level of each entry.
+type levelEncoder struct{}
+
+// Encode implements the log.Encoder interface.
+func (levelEncoder) Encode(e log.Entry) ([]byte, error) {
+	return []byte(log.LevelName(e.Level) + " "), nil
+}
@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestMulti tests routing entries to several sinks by level.
+func TestMulti(t *testing.T) {
+	t.Log("Given the need to write entries to several outputs.")
+	{
+		var stderr, file, alerts bytes.Buffer
+		m := log.NewMulti(
+			log.Sink{Writer: &stderr, Level: func() int { return log.USER }, Encoder: log.TextEncoder{}},
+			log.Sink{Writer: &file, Encoder: log.JSONEncoder{}},
+			log.Sink{Writer: &alerts, Level: func() int { return log.ERROR }, Encoder: levelEncoder{}},
+		)
+
+		lg := log.New(m, func() int { return log.DEV }, 0)
+
+		t.Log("\tWhen we log at different levels.")
+		{
+			lg.Dev("traceID", "FuncName", "Message %d", 1)
+			lg.User("traceID", "FuncName", "Message %d", 2)
+			lg.Error("traceID", "FuncName", errors.New("An error"), "Message %d", 3)
+
+			exp := "USER : traceID : FuncName : Message 2\nERROR : traceID : FuncName : An error : Message 3\n"
+			if stderr.String() == exp {
+				t.Logf("\t\t%v : Should write the USER entries as text.", Success)
+			} else {
+				t.Log("***>", stderr.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should write the USER entries as text.", Failed)
+			}
+
+			lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+			if len(lines) == 3 && strings.Contains(lines[0], `"level":"DEV"`) && strings.Contains(lines[0], `"msg":"Message 1"`) {
+				t.Logf("\t\t%v : Should write every entry as JSON.", Success)
+			} else {
+				t.Log("***>", file.String())
+				t.Errorf("\t\t%v : Should write every entry as JSON.", Failed)
+			}
+
+			if alerts.String() == "ERROR " {
+				t.Logf("\t\t%v : Should write the errors to their own sink.", Success)
+			} else {
+				t.Log("***>", alerts.String())
+				t.Errorf("\t\t%v : Should write the errors to their own sink.", Failed)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package log_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestRotatingFile tests rotating the log file by size and retaining the
+// rotated files.
+func TestRotatingFile(t *testing.T) {
+	t.Log("Given the need to rotate the log file.")
+	{
+		dir, err := ioutil.TempDir("", "log")
+		if err != nil {
+			t.Fatalf("\t\t%v : Should be able to create a directory : %v", Failed, err)
+		}
+		defer os.RemoveAll(dir)
+
+		name := filepath.Join(dir, "app.log")
+
+		t.Log("\tWhen the file reaches its maximum size.")
+		{
+			rf, err := log.NewRotatingFile(log.RotateConfig{Filename: name, MaxSize: 10, Compress: true, MaxBackups: 2})
+			if err != nil {
+				t.Fatalf("\t\t%v : Should be able to open the file : %v", Failed, err)
+			}
+
+			lg := log.New(rf, func() int { return log.DEV }, 0, log.WithEncoder(lineEncoder{}))
+			for i := 0; i < 4; i++ {
+				lg.Dev("traceID", "FuncName", "Message %d", i)
+			}
+
+			if err := rf.Close(); err != nil {
+				t.Fatalf("\t\t%v : Should close without error : %v", Failed, err)
+			}
+
+			data, _ := ioutil.ReadFile(name)
+			if string(data) == "Message 3\n" {
+				t.Logf("\t\t%v : Should write the last entry to a new file.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Errorf("\t\t%v : Should write the last entry to a new file.", Failed)
+			}
+
+			backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+			if len(backups) == 2 {
+				t.Logf("\t\t%v : Should keep two compressed backups.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should keep two compressed backups : %v", Failed, backups)
+			}
+
+			if len(backups) == 2 {
+				if got := gunzip(t, backups[1]); got == "Message 2\n" {
+					t.Logf("\t\t%v : Should compress the rotated entries.", Success)
+				} else {
+					t.Log("***>", got)
+					t.Errorf("\t\t%v : Should compress the rotated entries.", Failed)
+				}
+			}
+		}
+
+		t.Log("\tWhen the file is moved by another program.")
+		{
+			rf, err := log.NewRotatingFile(log.RotateConfig{Filename: name})
+			if err != nil {
+				t.Fatalf("\t\t%v : Should be able to open the file : %v", Failed, err)
+			}
+			defer rf.Close()
+
+			moved := filepath.Join(dir, "moved.log")
+			if err := os.Rename(name, moved); err != nil {
+				t.Fatalf("\t\t%v : Should be able to move the file : %v", Failed, err)
+			}
+
+			if err := rf.Reopen(); err != nil {
+				t.Fatalf("\t\t%v : Should reopen without error : %v", Failed, err)
+			}
+			rf.Write([]byte("Reopened\n"))
+
+			data, _ := ioutil.ReadFile(name)
+			if string(data) == "Reopened\n" {
+				t.Logf("\t\t%v : Should write to a new file after a reopen.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Errorf("\t\t%v : Should write to a new file after a reopen.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the file is older than the interval.")
+		{
+			rf, err := log.NewRotatingFile(log.RotateConfig{Filename: name, Interval: 10 * time.Millisecond})
+			if err != nil {
+				t.Fatalf("\t\t%v : Should be able to open the file : %v", Failed, err)
+			}
+			defer rf.Close()
+
+			time.Sleep(20 * time.Millisecond)
+			rf.Write([]byte("Rotated\n"))
+
+			data, _ := ioutil.ReadFile(name)
+			if string(data) == "Rotated\n" {
+				t.Logf("\t\t%v : Should rotate the file before writing.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Errorf("\t\t%v : Should rotate the file before writing.", Failed)
+			}
+		}
+	}
+}
+
+// TestRotatingFileFailure tests that the file is still written to after a
+// rotation fails.
+func TestRotatingFileFailure(t *testing.T) {
+	t.Log("Given the need to keep logging when the file can't be rotated.")
+	{
+		dir, err := ioutil.TempDir("", "log")
+		if err != nil {
+			t.Fatalf("\t\t%v : Should be able to create a directory : %v", Failed, err)
+		}
+		defer os.RemoveAll(dir)
+
+		name := filepath.Join(dir, "app.log")
+
+		t.Log("\tWhen the directory is read-only.")
+		{
+			if os.Geteuid() == 0 {
+				t.Logf("\t\t%v : Skipped, root can rename in a read-only directory.", Success)
+			} else {
+				rf, err := log.NewRotatingFile(log.RotateConfig{Filename: name, MaxSize: 10})
+				if err != nil {
+					t.Fatalf("\t\t%v : Should be able to open the file : %v", Failed, err)
+				}
+				defer rf.Close()
+
+				rf.Write([]byte("Message 1\n"))
+
+				os.Chmod(dir, 0555)
+				_, err = rf.Write([]byte("Message 2\n"))
+				os.Chmod(dir, 0755)
+
+				if err != nil {
+					t.Logf("\t\t%v : Should return the rotation error.", Success)
+				} else {
+					t.Errorf("\t\t%v : Should return the rotation error.", Failed)
+				}
+
+				data, _ := ioutil.ReadFile(name)
+				if string(data) == "Message 1\nMessage 2\n" {
+					t.Logf("\t\t%v : Should keep writing to the file.", Success)
+				} else {
+					t.Log("***>", string(data))
+					t.Errorf("\t\t%v : Should keep writing to the file.", Failed)
+				}
+			}
+		}
+
+		t.Log("\tWhen the file was removed before the rotation.")
+		{
+			rf, err := log.NewRotatingFile(log.RotateConfig{Filename: name})
+			if err != nil {
+				t.Fatalf("\t\t%v : Should be able to open the file : %v", Failed, err)
+			}
+			defer rf.Close()
+
+			os.Remove(name)
+
+			if err := rf.Rotate(); err != nil {
+				t.Logf("\t\t%v : Should fail to rename the file.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should fail to rename the file.", Failed)
+			}
+
+			if _, err := rf.Write([]byte("Recovered\n")); err != nil {
+				t.Errorf("\t\t%v : Should write after the failed rotation : %v", Failed, err)
+			}
+
+			data, _ := ioutil.ReadFile(name)
+			if string(data) == "Recovered\n" {
+				t.Logf("\t\t%v : Should reopen the file by name.", Success)
+			} else {
+				t.Log("***>", string(data))
+				t.Errorf("\t\t%v : Should reopen the file by name.", Failed)
+			}
+		}
+	}
+}
+
+// lineEncoder writes only the message of each entry.
+type lineEncoder struct{}
+
+// Encode implements the log.Encoder interface.
+func (lineEncoder) Encode(e log.Entry) ([]byte, error) {
+	return []byte(e.Message + "\n"), nil
+}
+
+// gunzip returns the content of a compressed file.
+func gunzip(t *testing.T, name string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("\t\t%v : Should be able to open %s : %v", Failed, name, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("\t\t%v : Should be able to read %s : %v", Failed, name, err)
+	}
+
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("\t\t%v : Should be able to read %s : %v", Failed, name, err)
+	}
+
+	return string(data)
+}
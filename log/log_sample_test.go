@@ -0,0 +1,174 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// syncBuffer is a bytes.Buffer that can be written to by the summary timer
+// while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements the io.Writer interface.
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+// String returns everything written.
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+// Reset discards everything written.
+func (sb *syncBuffer) Reset() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.buf.Reset()
+}
+
+// TestSampling tests sampling entries by call site and limiting them by key.
+func TestSampling(t *testing.T) {
+	t.Log("Given the need to reduce the entries logged from hot paths.")
+	{
+		var buf syncBuffer
+		sampling := log.Sampling{Interval: time.Hour, First: 2, Thereafter: 3}
+		lg := log.New(&buf, func() int { return log.DEV }, 0, log.WithSampling(sampling), log.WithSummary(200*time.Millisecond))
+
+		t.Log("\tWhen the same call site logs many times.")
+		{
+			for i := 1; i <= 10; i++ {
+				lg.Dev("traceID", "Read", "Message %d", i)
+			}
+
+			exp := "DEV : traceID : Read : Message 1\n" +
+				"DEV : traceID : Read : Message 2\n" +
+				"DEV : traceID : Read : Message 5\n" +
+				"DEV : traceID : Read : Message 8\n"
+
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should write the first entries and then every third.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should write the first entries and then every third.", Failed)
+			}
+		}
+
+		t.Log("\tWhen a key is limited.")
+		{
+			buf.Reset()
+
+			for _, key := range []string{"a", "a", "b", "a", "b"} {
+				lg.Limit(key, 1, time.Hour).User("traceID", "Process", "Key %s", key)
+			}
+
+			exp := "USER : traceID : Process : Key a\nUSER : traceID : Process : Key b\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should write the allowed entries for each key.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should write the allowed entries for each key.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the summary is due.")
+		{
+			buf.Reset()
+			time.Sleep(250 * time.Millisecond)
+
+			exp := "WARN :  : Sampling : Suppressed entries : sampled=6 limited=3\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should report the suppressed entries.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should report the suppressed entries.", Failed)
+			}
+		}
+
+		t.Log("\tWhen a limited logger sets its level.")
+		{
+			lg.Limit("a", 1, time.Hour).SetLevel(func() int { return log.NONE })
+
+			buf.Reset()
+			lg.User("traceID", "main", "Message")
+
+			if buf.String() == "USER : traceID : main : Message\n" {
+				t.Logf("\t\t%v : Should not change the level of the logger.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Errorf("\t\t%v : Should not change the level of the logger.", Failed)
+			}
+		}
+	}
+}
+
+// TestSamplingDefaults tests the default sampling settings, the number of
+// keys tracked and writing the summary before exiting.
+func TestSamplingDefaults(t *testing.T) {
+	t.Log("Given the need to sample entries with the default settings.")
+	{
+		var buf syncBuffer
+		lg := log.New(&buf, func() int { return log.DEV }, 0, log.WithSampling(log.Sampling{}), log.WithSummary(time.Hour), log.WithExit(func(int) {}))
+
+		t.Log("\tWhen First isn't set.")
+		{
+			for i := 0; i < 110; i++ {
+				lg.Dev("traceID", "Read", "Message %d", i)
+			}
+
+			if n := strings.Count(buf.String(), "\n"); n == 100 {
+				t.Logf("\t\t%v : Should write the first 100 entries.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should write the first 100 entries : got %d", Failed, n)
+			}
+		}
+
+		t.Log("\tWhen more keys are limited than are tracked.")
+		{
+			buf.Reset()
+
+			lg.Limit("first", 1, time.Hour).User("traceID", "Process", "Key first")
+			lg.Limit("first", 1, time.Hour).User("traceID", "Process", "Key first")
+			for i := 0; i < 1024; i++ {
+				lg.Limit(fmt.Sprint(i), 1, time.Hour).User("traceID", "Process", "Key %d", i)
+			}
+			lg.Limit("first", 1, time.Hour).User("traceID", "Process", "Key first")
+
+			if n := strings.Count(buf.String(), "Key first\n"); n == 2 {
+				t.Logf("\t\t%v : Should forget the least recently used key.", Success)
+			} else {
+				t.Errorf("\t\t%v : Should forget the least recently used key : got %d", Failed, n)
+			}
+		}
+
+		t.Log("\tWhen a fatal message is logged.")
+		{
+			buf.Reset()
+			lg.Fatal("traceID", "main", "Giving up")
+
+			exp := "FATAL : traceID : main : Giving up\nWARN :  : Sampling : Suppressed entries : sampled=934 limited=1\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should report the suppressed entries before exiting.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should report the suppressed entries before exiting.", Failed)
+			}
+		}
+	}
+}
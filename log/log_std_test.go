@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestStandard tests the methods of the embedded standard library logger.
+func TestStandard(t *testing.T) {
+	t.Log("Given the need to use a Logger like the standard library logger.")
+	{
+		var buf bytes.Buffer
+		lg := log.New(&buf, func() int { return log.NONE }, 0, log.WithEncoder(log.JSONEncoder{}))
+
+		t.Log("\tWhen we call Printf with logging disabled.")
+		{
+			lg.Printf("Message %d", 1)
+
+			var std *stdlog.Logger = lg.Logger
+			std.Println("Message", 2)
+
+			exp := "Message 1\nMessage 2\n"
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should write the output as is.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should write the output as is.", Failed)
+			}
+		}
+
+		t.Log("\tWhen the output is replaced.")
+		{
+			var out bytes.Buffer
+			lg = log.New(&buf, func() int { return log.USER }, log.Ldefault)
+			lg.Named("tcp").SetOutput(&out)
+			lg.SetFlags(0)
+
+			lg.SetPrefix("app: ")
+			lg.Print("Message 3")
+			lg.User("traceID", "FuncName", "Message 4")
+
+			exp := "app: Message 3\nUSER : traceID : FuncName : Message 4\n"
+			if out.String() == exp && lg.Flags() == 0 && lg.Writer() == &out {
+				t.Logf("\t\t%v : Should write to the new output with the new flags.", Success)
+			} else {
+				t.Log("***>", out.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should write to the new output with the new flags.", Failed)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// TestStructured tests logging messages with key and value pairs.
+func TestStructured(t *testing.T) {
+	t.Log("Given the need to log structured messages.")
+	{
+		t.Log("\tWhen we set the logging level to USER.")
+		{
+			var buf bytes.Buffer
+			lg := log.New(&buf, func() int { return log.USER }, 0)
+
+			lg.Devw("traceID", "FuncName", "Dropped")
+			lg.Userw("traceID", "FuncName", "Request", "user", "bill", "count", 3, log.Duration("took", 2*time.Second))
+			lg.Errorw("traceID", "FuncName", errors.New("An error"), "Failed", "query", "select * from users", "dangling")
+
+			exp := "USER : traceID : FuncName : Request : user=bill count=3 took=2s\n" +
+				"ERROR : traceID : FuncName : An error : Failed : query=\"select * from users\" !BADKEY=dangling\n"
+
+			if buf.String() == exp {
+				t.Logf("\t\t%v : Should log the expected trace lines.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Log("***>", exp)
+				t.Errorf("\t\t%v : Should log the expected trace lines.", Failed)
+			}
+		}
+
+		t.Log("\tWhen we log through the default logger.")
+		{
+			var buf bytes.Buffer
+			log.Init(&buf, func() int { return log.DEV }, log.Lshortfile)
+
+			log.Devw("traceID", "FuncName", "Message", "key", "value")
+
+			if strings.HasPrefix(buf.String(), "log_structured_test.go:") && strings.HasSuffix(buf.String(), ": DEV : traceID : FuncName : Message : key=value\n") {
+				t.Logf("\t\t%v : Should log the caller and fields.", Success)
+			} else {
+				t.Log("***>", buf.String())
+				t.Errorf("\t\t%v : Should log the caller and fields.", Failed)
+			}
+		}
+	}
+}
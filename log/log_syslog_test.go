@@ -0,0 +1,139 @@
+package log_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// listen starts a unix datagram listener standing in for the local daemon.
+func listen(t *testing.T, name string) (*net.UnixConn, func()) {
+	dir, err := ioutil.TempDir("", "log")
+	if err != nil {
+		t.Fatalf("\t\t%v : Should be able to create a directory : %v", Failed, err)
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: filepath.Join(dir, name), Net: "unixgram"})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("\t\t%v : Should be able to listen on a socket : %v", Failed, err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// receive returns the next datagram sent to the listener.
+func receive(t *testing.T, conn *net.UnixConn) string {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	buf := make([]byte, 65536)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("\t\t%v : Should be able to receive a message : %v", Failed, err)
+	}
+
+	return string(buf[:n])
+}
+
+// TestSyslog tests sending entries to a syslog socket.
+func TestSyslog(t *testing.T) {
+	t.Log("Given the need to send entries to the syslog daemon.")
+	{
+		conn, cleanup := listen(t, "log")
+		defer cleanup()
+
+		sl, err := log.NewSyslog(log.SyslogConfig{Path: conn.LocalAddr().String(), Facility: log.FacilityLocal0, Tag: "app", Hostname: "host"})
+		if err != nil {
+			t.Fatalf("\t\t%v : Should be able to connect : %v", Failed, err)
+		}
+		defer sl.Close()
+
+		lg := log.New(sl, func() int { return log.DEV }, 0)
+
+		t.Log("\tWhen we log a message.")
+		{
+			lg.User("traceID", "FuncName", "Message %d", 1)
+			got := receive(t, conn)
+
+			prefix := "<134>1 "
+			suffix := fmt.Sprintf(" host app %d - - USER : traceID : FuncName : Message 1", os.Getpid())
+			if strings.HasPrefix(got, prefix) && strings.HasSuffix(got, suffix) {
+				t.Logf("\t\t%v : Should frame the message with the priority and header.", Success)
+			} else {
+				t.Log("***>", got)
+				t.Log("***>", prefix+"TIMESTAMP"+suffix)
+				t.Errorf("\t\t%v : Should frame the message with the priority and header.", Failed)
+			}
+		}
+
+		t.Log("\tWhen we log an error.")
+		{
+			lg.Error("traceID", "FuncName", errors.New("An error"), "Message")
+
+			if got := receive(t, conn); strings.HasPrefix(got, "<131>1 ") {
+				t.Logf("\t\t%v : Should map the level to the err severity.", Success)
+			} else {
+				t.Log("***>", got)
+				t.Errorf("\t\t%v : Should map the level to the err severity.", Failed)
+			}
+		}
+	}
+}
+
+// TestJournal tests sending entries to a journal socket.
+func TestJournal(t *testing.T) {
+	t.Log("Given the need to send entries to the journal.")
+	{
+		conn, cleanup := listen(t, "socket")
+		defer cleanup()
+
+		j, err := log.NewJournal(log.JournalConfig{Path: conn.LocalAddr().String(), Tag: "app"})
+		if err != nil {
+			t.Fatalf("\t\t%v : Should be able to connect : %v", Failed, err)
+		}
+		defer j.Close()
+
+		lg := log.New(j, func() int { return log.DEV }, 0)
+
+		t.Log("\tWhen we log a structured message.")
+		{
+			lg.Errorw("traceID", "FuncName", errors.New("An error"), "Message", "user-id", 42)
+			got := receive(t, conn)
+
+			exp := []string{"MESSAGE=Message\n", "PRIORITY=3\n", "SYSLOG_IDENTIFIER=app\n", "TRACE_ID=traceID\n", "CODE_FUNC=FuncName\n", "ERROR=An error\n", "USER_ID=42\n"}
+			for _, e := range exp {
+				if !strings.Contains(got, e) {
+					t.Log("***>", got)
+					t.Fatalf("\t\t%v : Should send the field %q.", Failed, e)
+				}
+			}
+			t.Logf("\t\t%v : Should send the entry as journal fields.", Success)
+		}
+
+		t.Log("\tWhen the message has more than one line.")
+		{
+			lg.User("traceID", "FuncName", "Line 1\nLine 2")
+			got := receive(t, conn)
+
+			exp := "MESSAGE\n\x0d\x00\x00\x00\x00\x00\x00\x00Line 1\nLine 2\n"
+			if strings.HasPrefix(got, exp) {
+				t.Logf("\t\t%v : Should send the message with its length.", Success)
+			} else {
+				t.Logf("***> %q", got)
+				t.Logf("***> %q", exp)
+				t.Errorf("\t\t%v : Should send the message with its length.", Failed)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// EntryWriter is implemented by outputs that are given each entry rather than
+// its encoded bytes. When the writer given to New or Init implements it, the
+// Logger passes the entries to WriteEntry and its own Encoder isn't used.
+type EntryWriter interface {
+	WriteEntry(e Entry) error
+}
+
+// entryOutput is implemented by outputs that wrap another writer and only
+// accept entries when the wrapped writer does, such as Async.
+type entryOutput interface {
+	entries() bool
+}
+
+// acceptsEntries reports whether the output should be given entries rather
+// than encoded bytes.
+func acceptsEntries(w io.Writer) bool {
+	if eo, ok := w.(entryOutput); ok {
+		return eo.entries()
+	}
+
+	_, ok := w.(EntryWriter)
+	return ok
+}
+
+// Sink is an output of a Multi with its own level and Encoder.
+type Sink struct {
+	Writer  io.Writer
+	Level   func() int // Level handler for the sink, nil writes every entry.
+	Encoder Encoder    // Encoder for the sink, defaults to a TextEncoder using Ldefault.
+}
+
+// Multi routes each entry to a set of sinks, each writing the entries at or
+// above its own level with its own Encoder:
+//
+//	m := log.NewMulti(
+//		log.Sink{Writer: os.Stderr, Level: func() int { return log.USER }},
+//		log.Sink{Writer: file, Encoder: log.JSONEncoder{}},
+//		log.Sink{Writer: alerts, Level: func() int { return log.ERROR }},
+//	)
+//
+//	log.Init(m, func() int { return log.DEV }, log.Ldefault)
+//
+// The level of the Logger is applied first, so it must enable the most
+// verbose level any sink writes.
+type Multi struct {
+	mu    sync.Mutex
+	sinks []Sink
+}
+
+// NewMulti returns a Multi writing to the sinks.
+func NewMulti(sinks ...Sink) *Multi {
+	m := Multi{
+		sinks: make([]Sink, len(sinks)),
+	}
+
+	for i, s := range sinks {
+		if s.Encoder == nil {
+			s.Encoder = TextEncoder{Flags: Ldefault}
+		}
+		m.sinks[i] = s
+	}
+
+	return &m
+}
+
+// WriteEntry implements the EntryWriter interface. The entry is encoded and
+// written by every sink whose level enables it. The first error is returned
+// after every sink was written to.
+func (m *Multi) WriteEntry(e Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var first error
+	for _, s := range m.sinks {
+		if s.Level != nil {
+			min := s.Level()
+			if min == NONE || e.Level < min {
+				continue
+			}
+		}
+
+		data, err := s.Encoder.Encode(e)
+		if err == nil {
+			_, err = s.Writer.Write(data)
+		}
+
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Write implements the io.Writer interface. Output that doesn't come from an
+// entry, such as from the embedded standard logger, is written as is to every
+// sink.
+func (m *Multi) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var first error
+	for _, s := range m.sinks {
+		if _, err := s.Writer.Write(p); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	if first != nil {
+		return 0, first
+	}
+
+	return len(p), nil
+}
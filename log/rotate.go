@@ -0,0 +1,370 @@
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupFormat is the layout of the timestamp in the name of a rotated file.
+const backupFormat = "2006-01-02T15-04-05.000"
+
+// RotateConfig provides the settings for a RotatingFile.
+type RotateConfig struct {
+	Filename       string        // Path of the file written to, required.
+	MaxSize        int64         // Rotate when the file would exceed this many bytes, 0 disables.
+	Interval       time.Duration // Rotate when the file is older than this, 0 disables.
+	Compress       bool          // Compress rotated files with gzip.
+	MaxBackups     int           // Number of rotated files to keep, 0 keeps them all.
+	MaxAge         time.Duration // Remove rotated files older than this, 0 keeps them all.
+	ReopenOnHangup bool          // Reopen the file when the process receives SIGHUP.
+}
+
+// RotatingFile is an io.Writer that writes to a file and rotates it once it
+// reaches a maximum size or age, so it can be given to New or Init instead of
+// relying on an external logrotate:
+//
+//	rf, err := log.NewRotatingFile(log.RotateConfig{
+//		Filename:   "/var/log/app/app.log",
+//		MaxSize:    100 << 20,
+//		Compress:   true,
+//		MaxBackups: 10,
+//	})
+//
+// A rotated file is renamed with the time of the rotation, so app.log becomes
+// app-2006-01-02T15-04-05.000.log, and is then compressed and pruned in the
+// background.
+type RotatingFile struct {
+	cfg RotateConfig
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	mill   sync.Mutex
+	wg     sync.WaitGroup
+	stop   func()
+	closed bool
+}
+
+// NewRotatingFile opens the file named in the configuration, creating it and
+// its directory when missing, and appends to it.
+func NewRotatingFile(cfg RotateConfig) (*RotatingFile, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("a filename is required")
+	}
+
+	rf := RotatingFile{
+		cfg: cfg,
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReopenOnHangup {
+		rf.stop = reopenOnHangup(&rf)
+	}
+
+	return &rf, nil
+}
+
+// Write implements the io.Writer interface. The file is rotated first when
+// the write would take it over MaxSize or it is older than Interval. When the
+// rotation fails the entry is still written to the current file and the
+// rotation error is returned.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return 0, ErrClosed
+	}
+
+	var rerr error
+	if rf.size > 0 && rf.due(int64(len(p))) {
+		rerr = rf.rotate()
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+
+	if err == nil {
+		err = rerr
+	}
+
+	return n, err
+}
+
+// Rotate closes the current file, renames it with the current time and opens
+// a new one.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return ErrClosed
+	}
+
+	return rf.rotate()
+}
+
+// Reopen closes and reopens the file by name without rotating it. This is
+// needed when the file was moved by another program, such as logrotate.
+func (rf *RotatingFile) Reopen() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.closed {
+		return ErrClosed
+	}
+
+	old := rf.file
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	return old.Close()
+}
+
+// Close closes the file and waits for rotated files to be compressed and
+// pruned.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	if rf.closed {
+		rf.mu.Unlock()
+		return nil
+	}
+	rf.closed = true
+	err := rf.file.Close()
+	rf.mu.Unlock()
+
+	if rf.stop != nil {
+		rf.stop()
+	}
+
+	rf.wg.Wait()
+
+	return err
+}
+
+// due reports if the file must be rotated before writing n more bytes.
+func (rf *RotatingFile) due(n int64) bool {
+	if rf.cfg.MaxSize > 0 && rf.size+n > rf.cfg.MaxSize {
+		return true
+	}
+
+	if rf.cfg.Interval > 0 && time.Since(rf.opened) >= rf.cfg.Interval {
+		return true
+	}
+
+	return false
+}
+
+// open opens the file for appending and records its size.
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.cfg.Filename), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(rf.cfg.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	rf.file = f
+	rf.size = info.Size()
+	rf.opened = time.Now()
+
+	return nil
+}
+
+// rotate renames the current file to a backup name and opens a new file. The
+// backup is compressed and old backups are pruned in the background. When the
+// rotation fails the file is reopened by name so later writes still succeed.
+func (rf *RotatingFile) rotate() error {
+	backup := rf.backupName(time.Now().UTC())
+
+	if err := rf.file.Close(); err != nil {
+		return rf.recover(err)
+	}
+
+	if err := os.Rename(rf.cfg.Filename, backup); err != nil {
+		return rf.recover(err)
+	}
+
+	if err := rf.open(); err != nil {
+		return rf.recover(err)
+	}
+
+	rf.wg.Add(1)
+	go func() {
+		defer rf.wg.Done()
+		rf.millRun(backup)
+	}()
+
+	return nil
+}
+
+// recover reopens the file by name for appending after a failed rotation and
+// returns the error that made the rotation fail.
+func (rf *RotatingFile) recover(err error) error {
+	if oerr := rf.open(); oerr != nil {
+		return fmt.Errorf("%v : reopening : %v", err, oerr)
+	}
+
+	return err
+}
+
+// backupName returns an unused name for a backup made at t. The time is moved
+// forward a millisecond at a time when a backup for t already exists.
+func (rf *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := rf.parts()
+
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupFormat)+ext)
+
+		_, err := os.Stat(name)
+		_, errgz := os.Stat(name + ".gz")
+		if os.IsNotExist(err) && os.IsNotExist(errgz) {
+			return name
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// parts splits the filename into the directory, the prefix of the backup
+// names and the extension.
+func (rf *RotatingFile) parts() (dir string, prefix string, ext string) {
+	dir = filepath.Dir(rf.cfg.Filename)
+	base := filepath.Base(rf.cfg.Filename)
+	ext = filepath.Ext(base)
+
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// millRun compresses the backup when configured and removes the backups that
+// exceed MaxBackups or MaxAge. Runs are serialized so they don't overlap.
+func (rf *RotatingFile) millRun(backup string) {
+	rf.mill.Lock()
+	defer rf.mill.Unlock()
+
+	if rf.cfg.Compress {
+		if err := compress(backup); err != nil {
+			return
+		}
+	}
+
+	if rf.cfg.MaxBackups <= 0 && rf.cfg.MaxAge <= 0 {
+		return
+	}
+
+	backups, err := rf.backups()
+	if err != nil {
+		return
+	}
+
+	for i, b := range backups {
+		if rf.cfg.MaxBackups > 0 && i >= rf.cfg.MaxBackups {
+			os.Remove(b.name)
+			continue
+		}
+
+		if rf.cfg.MaxAge > 0 && time.Since(b.t) > rf.cfg.MaxAge {
+			os.Remove(b.name)
+		}
+	}
+}
+
+// backup represents a rotated file and the time it was rotated.
+type backup struct {
+	name string
+	t    time.Time
+}
+
+// backups returns the rotated files, newest first.
+func (rf *RotatingFile) backups() ([]backup, error) {
+	dir, prefix, ext := rf.parts()
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var bs []backup
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		ts := strings.TrimPrefix(name, prefix)
+		ts = strings.TrimSuffix(ts, ".gz")
+		if !strings.HasSuffix(ts, ext) {
+			continue
+		}
+
+		t, err := time.Parse(backupFormat, strings.TrimSuffix(ts, ext))
+		if err != nil {
+			continue
+		}
+
+		bs = append(bs, backup{name: filepath.Join(dir, name), t: t})
+	}
+
+	sort.Slice(bs, func(i, j int) bool { return bs[i].t.After(bs[j].t) })
+
+	return bs, nil
+}
+
+// compress writes a gzip copy of the file named name to name.gz and removes
+// the original.
+func compress(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(name)
+}
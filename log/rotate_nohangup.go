@@ -0,0 +1,9 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package log
+
+// reopenOnHangup does nothing since the platform has no SIGHUP.
+func reopenOnHangup(rf *RotatingFile) func() {
+	return func() {}
+}
@@ -0,0 +1,207 @@
+package log
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// maxLimits is the number of keys tracked by Limit. The least recently used
+// key is removed to make room for a new one.
+const maxLimits = 1024
+
+// defaultFirst is the number of entries written from each call site per
+// interval when Sampling doesn't set First.
+const defaultFirst = 100
+
+// Sampling provides the settings for sampling entries by call site. Within
+// each Interval the First entries logged from a call site are written, then
+// every Thereafter entry.
+type Sampling struct {
+	Interval   time.Duration // Length of each sampling period, defaults to 1s.
+	First      int           // Entries written from each call site per interval, defaults to 100.
+	Thereafter int           // Write every Thereafter entry after First, 0 drops them.
+}
+
+// WithSampling samples the entries logged from each call site so hot paths
+// don't flood the output.
+func WithSampling(s Sampling) Option {
+	return func(l *Logger) {
+		if s.Interval <= 0 {
+			s.Interval = time.Second
+		}
+		if s.First <= 0 {
+			s.First = defaultFirst
+		}
+		l.smp.sampling = &s
+	}
+}
+
+// WithSummary writes a WARN entry at most once per interval reporting how
+// many entries were suppressed by sampling and Limit since the last summary.
+// The summary is written an interval after the first suppressed entry, and
+// by the Fatal calls before the exit hooks run.
+func WithSummary(interval time.Duration) Option {
+	return func(l *Logger) {
+		l.smp.summary = interval
+	}
+}
+
+// Limit returns a Logger that writes at most n entries per period for the
+// key, such as the address of a client, and suppresses the rest. Loggers
+// returned for the same key share the count:
+//
+//	log.Limit(ipAddress, 10, time.Second).Dev(traceID, "Read", "Completed")
+func (l *Logger) Limit(key string, n int, per time.Duration) *Logger {
+	r := l.root()
+
+	return &Logger{Logger: r.Logger, parent: r, name: l.name, limit: &limit{key: key, n: n, per: per}}
+}
+
+// limit represents the settings of a Logger returned by Limit.
+type limit struct {
+	key string
+	n   int
+	per time.Duration
+}
+
+// window counts the entries in the current period for a call site or key.
+type window struct {
+	start time.Time
+	per   time.Duration
+	n     int
+}
+
+// count adds an entry to the window, starting a new period when the current
+// one has ended, and returns the number of entries in the period.
+func (w *window) count(now time.Time) int {
+	if now.Sub(w.start) >= w.per {
+		w.start = now
+		w.n = 0
+	}
+
+	w.n++
+	return w.n
+}
+
+// sampler holds the sampling and rate limiting state shared by a Logger and
+// the Loggers created from it.
+type sampler struct {
+	mu       sync.Mutex
+	sampling *Sampling
+	summary  time.Duration
+
+	sites  map[uintptr]*window
+	limits map[string]*list.Element
+	lru    *list.List
+
+	timer   *time.Timer
+	sampled int
+	limited int
+}
+
+// keyWindow is the window of a key tracked by Limit.
+type keyWindow struct {
+	key string
+	window
+}
+
+// allow reports whether an entry logged from the caller at the given depth
+// is written, applying the limit of the Logger and then the sampling. It
+// writes the summary when it is due. The caller must hold the read lock.
+func (l *Logger) allow(calldepth int, lim *limit) bool {
+	s := l.smp
+	if s == nil || (s.sampling == nil && lim == nil && s.summary <= 0) {
+		return true
+	}
+
+	now := time.Now()
+	ok := true
+
+	s.mu.Lock()
+
+	if lim != nil {
+		if s.limitWindow(lim).count(now) > lim.n {
+			s.limited++
+			ok = false
+		}
+	}
+
+	if ok && s.sampling != nil {
+		if pc, _, _, found := runtime.Caller(calldepth); found {
+			w, found := s.sites[pc]
+			if !found {
+				if s.sites == nil {
+					s.sites = make(map[uintptr]*window)
+				}
+				w = &window{per: s.sampling.Interval}
+				s.sites[pc] = w
+			}
+
+			n := w.count(now) - s.sampling.First
+			if n > 0 && (s.sampling.Thereafter <= 0 || n%s.sampling.Thereafter != 0) {
+				s.sampled++
+				ok = false
+			}
+		}
+	}
+
+	if !ok && s.summary > 0 && s.timer == nil {
+		s.timer = time.AfterFunc(s.summary, l.summarize)
+	}
+
+	s.mu.Unlock()
+
+	return ok
+}
+
+// summarize writes the summary of the suppressed entries when there are any.
+func (l *Logger) summarize() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	s := l.smp
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	sampled, limited := s.sampled, s.limited
+	s.sampled, s.limited = 0, 0
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	if (sampled > 0 || limited > 0) && l.enabled("", WARN) {
+		l.write(1, Entry{Level: WARN, FuncName: "Sampling", Message: "Suppressed entries", Fields: []Field{Int("sampled", sampled), Int("limited", limited)}})
+	}
+}
+
+// limitWindow returns the window for the key of the limit. Once maxLimits
+// keys are tracked, the least recently used key is removed to make room for a
+// new one. The caller must hold the sampler lock.
+func (s *sampler) limitWindow(lim *limit) *window {
+	if e, found := s.limits[lim.key]; found {
+		s.lru.MoveToFront(e)
+		return &e.Value.(*keyWindow).window
+	}
+
+	if s.limits == nil {
+		s.limits = make(map[string]*list.Element)
+		s.lru = list.New()
+	}
+
+	if s.lru.Len() >= maxLimits {
+		e := s.lru.Back()
+		s.lru.Remove(e)
+		delete(s.limits, e.Value.(*keyWindow).key)
+	}
+
+	kw := keyWindow{key: lim.key, window: window{per: lim.per}}
+	s.limits[lim.key] = s.lru.PushFront(&kw)
+
+	return &kw.window
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"io"
+)
+
+// stdWriter is the writer of the embedded standard logger. It writes to the
+// current output of the Logger under its write lock, so the output of the
+// standard logger methods doesn't interleave with the entries.
+type stdWriter struct {
+	l *Logger
+}
+
+// Write implements the io.Writer interface.
+func (sw stdWriter) Write(p []byte) (int, error) {
+	r := sw.l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	r.wmu.Lock()
+	defer r.wmu.Unlock()
+
+	return r.out.Write(p)
+}
+
+// SetOutput replaces the writer the Logger and the Loggers created from it
+// write to, for both the entries and the standard logger methods.
+func (l *Logger) SetOutput(w io.Writer) {
+	r := l.root()
+
+	r.mu.Lock()
+	{
+		r.out = w
+	}
+	r.mu.Unlock()
+}
+
+// Writer returns the writer the Logger writes to.
+func (l *Logger) Writer() io.Writer {
+	r := l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.out
+}
+
+// SetFlags replaces the flags of the standard logger methods, and of the
+// entries when the Logger uses a TextEncoder.
+func (l *Logger) SetFlags(flags int) {
+	r := l.root()
+
+	r.mu.Lock()
+	{
+		if _, ok := r.enc.(TextEncoder); ok {
+			r.enc = TextEncoder{Flags: flags}
+		}
+	}
+	r.mu.Unlock()
+
+	r.Logger.SetFlags(flags)
+}
@@ -0,0 +1,204 @@
+package log
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Facility represents the syslog facility of the messages sent by a Syslog.
+type Facility int
+
+// Set of syslog facilities. The kernel facility isn't included since it is
+// reserved for the kernel.
+const (
+	FacilityUser Facility = iota + 1
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+)
+
+// Set of local use syslog facilities.
+const (
+	FacilityLocal0 Facility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// severity maps a level to a syslog severity: debug for TRACE and DEV, info
+// for USER, warning for WARN, err for ERROR and crit for FATAL and PANIC.
+func severity(level int) int {
+	switch {
+	case level <= DEV:
+		return 7
+	case level == USER:
+		return 6
+	case level == WARN:
+		return 4
+	case level == ERROR:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// SyslogConfig provides the settings for a Syslog.
+type SyslogConfig struct {
+	Path     string   // Path of the syslog socket, defaults to /dev/log.
+	Facility Facility // Facility of the messages, defaults to FacilityUser.
+	Tag      string   // Name of the application, defaults to the program name.
+	Hostname string   // Name of the host, defaults to the name the kernel reports.
+	Encoder  Encoder  // Encoder for the message, defaults to a TextEncoder without flags.
+}
+
+// Syslog sends entries to the local syslog daemon over its unix datagram
+// socket using RFC 5424 framing. It is an EntryWriter so the severity of each
+// message is taken from the level of the entry:
+//
+//	sl, err := log.NewSyslog(log.SyslogConfig{Facility: log.FacilityLocal0})
+//	log.Init(sl, logLevel, 0)
+type Syslog struct {
+	cfg  SyslogConfig
+	pid  string
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog connects to the syslog socket.
+func NewSyslog(cfg SyslogConfig) (*Syslog, error) {
+	if cfg.Path == "" {
+		cfg.Path = "/dev/log"
+	}
+
+	if cfg.Facility == 0 {
+		cfg.Facility = FacilityUser
+	}
+
+	if cfg.Tag == "" {
+		cfg.Tag = filepath.Base(os.Args[0])
+	}
+
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+
+	if cfg.Encoder == nil {
+		cfg.Encoder = TextEncoder{}
+	}
+
+	sl := Syslog{
+		cfg: cfg,
+		pid: strconv.Itoa(os.Getpid()),
+	}
+
+	conn, err := net.Dial("unixgram", cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	sl.conn = conn
+
+	return &sl, nil
+}
+
+// WriteEntry implements the EntryWriter interface.
+func (sl *Syslog) WriteEntry(e Entry) error {
+	msg, err := sl.cfg.Encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	return sl.send(e, msg)
+}
+
+// Write implements the io.Writer interface. Output that doesn't come from an
+// entry, such as from the embedded standard logger, is sent with the info
+// severity.
+func (sl *Syslog) Write(p []byte) (int, error) {
+	if err := sl.send(Entry{Level: USER}, p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to the syslog socket.
+func (sl *Syslog) Close() error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	return sl.conn.Close()
+}
+
+// send frames the message for the entry and sends it, reconnecting once when
+// the daemon was restarted.
+func (sl *Syslog) send(e Entry, msg []byte) error {
+	var buf bytes.Buffer
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(int(sl.cfg.Facility)*8 + severity(e.Level)))
+	buf.WriteString(">1 ")
+	if e.Time.IsZero() {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteString(e.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(header(sl.cfg.Hostname))
+	buf.WriteByte(' ')
+	buf.WriteString(header(sl.cfg.Tag))
+	buf.WriteByte(' ')
+	buf.WriteString(sl.pid)
+	buf.WriteString(" - - ")
+	buf.Write(bytes.TrimRight(msg, "\n"))
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if _, err := sl.conn.Write(buf.Bytes()); err != nil {
+		conn, derr := net.Dial("unixgram", sl.cfg.Path)
+		if derr != nil {
+			return err
+		}
+
+		sl.conn.Close()
+		sl.conn = conn
+
+		_, err = sl.conn.Write(buf.Bytes())
+		return err
+	}
+
+	return nil
+}
+
+// header returns the value for a header field, which must be printable ASCII
+// without spaces, using the nil value when the field is empty.
+func header(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' {
+			b[i] = '_'
+		}
+	}
+
+	return string(b)
+}
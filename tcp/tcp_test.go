@@ -14,9 +14,6 @@ import (
 // TestTCP provide a test of listening for a connection and
 // echoing the data back.
 func TestTCP(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	t.Log("Given the need to listen and process TCP data.")
 	{
 		// Create a configuration.
@@ -89,9 +86,6 @@ func TestTCP(t *testing.T) {
 
 // Test tcp.Addr works correctly.
 func TestTCPAddr(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	t.Log("Given the need to listen on any open port and know that bound address.")
 	{
 		// Create a configuration.
@@ -144,9 +138,6 @@ func TestTCPAddr(t *testing.T) {
 
 // TestDropConnections tests we can drop connections when configured.
 func TestDropConnections(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	t.Log("Given the need to drop TCP connections.")
 	{
 		// Create a configuration.
@@ -195,9 +186,6 @@ func TestDropConnections(t *testing.T) {
 
 // TestRateLimit tests we can drop connections when they come in too fast.
 func TestRateLimit(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	const ratelimit = 1 * time.Second
 
 	t.Log("Given the need to drop TCP connections.")
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+)
+
+// Capture is a Logger that keeps the entries logged during a single test in
+// memory so the test can assert on them. Each test creates its own Capture,
+// so tests using them can run in parallel:
+//
+//	c := tests.NewCapture(t, log.DEV)
+//	svc := NewService(c.Logger)
+//	...
+//	c.AssertLogged(t, log.ERROR, "connection refused")
+//
+// The captured entries are written to the test log when the test fails.
+type Capture struct {
+	*log.Logger
+
+	mu      sync.Mutex
+	entries []log.Entry
+	exited  bool
+	code    int
+}
+
+// NewCapture returns a Capture logging at the given level. Fatal calls made
+// through it record the exit code instead of terminating the test binary.
+func NewCapture(t *testing.T, level int) *Capture {
+	var c Capture
+	c.Logger = log.New(&c, func() int { return level }, 0, log.WithExit(c.exit))
+
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		for _, e := range c.Entries("") {
+			t.Logf("%s : %s : %s : %s : %s", log.LevelName(e.Level), e.TraceID, e.FuncName, e.Caller(), e.Message)
+		}
+	})
+
+	return &c
+}
+
+// WriteEntry implements the log.EntryWriter interface.
+func (c *Capture) WriteEntry(e log.Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = append(c.entries, e)
+	return nil
+}
+
+// Write implements the io.Writer interface. Output that doesn't come from an
+// entry, such as from the embedded standard logger, is kept as a USER entry.
+func (c *Capture) Write(p []byte) (int, error) {
+	c.WriteEntry(log.Entry{Level: log.USER, Message: strings.TrimRight(string(p), "\n")})
+	return len(p), nil
+}
+
+// Entries returns the captured entries for the trace ID, or every captured
+// entry when the trace ID is empty.
+func (c *Capture) Entries(traceID string) []log.Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entries []log.Entry
+	for _, e := range c.entries {
+		if traceID == "" || e.TraceID == traceID {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// Logged reports whether an entry at the level was captured whose message or
+// error contains the substring.
+func (c *Capture) Logged(level int, substring string) bool {
+	for _, e := range c.Entries("") {
+		if e.Level != level {
+			continue
+		}
+
+		if strings.Contains(e.Message, substring) || (e.Err != nil && strings.Contains(e.Err.Error(), substring)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AssertLogged fails the test when no entry at the level was captured whose
+// message or error contains the substring.
+func (c *Capture) AssertLogged(t *testing.T, level int, substring string) {
+	t.Helper()
+
+	if c.Logged(level, substring) {
+		t.Logf("\t\t%v : Should log %s %q.", Success, log.LevelName(level), substring)
+		return
+	}
+
+	t.Errorf("\t\t%v : Should log %s %q.", Failed, log.LevelName(level), substring)
+}
+
+// AssertNotLogged fails the test when an entry at the level was captured
+// whose message or error contains the substring.
+func (c *Capture) AssertNotLogged(t *testing.T, level int, substring string) {
+	t.Helper()
+
+	if !c.Logged(level, substring) {
+		t.Logf("\t\t%v : Should not log %s %q.", Success, log.LevelName(level), substring)
+		return
+	}
+
+	t.Errorf("\t\t%v : Should not log %s %q.", Failed, log.LevelName(level), substring)
+}
+
+// Exited returns the exit code of the last Fatal call and reports whether
+// one was made.
+func (c *Capture) Exited() (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.code, c.exited
+}
+
+// Reset discards the captured entries and the recorded exit.
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+	c.exited = false
+	c.code = 0
+}
+
+// exit records the exit code of a Fatal call.
+func (c *Capture) exit(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exited = true
+	c.code = code
+}
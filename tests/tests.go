@@ -18,15 +18,22 @@ var (
 )
 
 // Logdash is the central buffer where all logs are stored.
+//
+// Deprecated: the buffer is shared by every test. Use NewCapture to capture
+// the entries of a single test.
 var Logdash bytes.Buffer
 
 // ResetLog resets the contents of Logdash.
+//
+// Deprecated: use NewCapture.
 func ResetLog() {
 	Logdash.Reset()
 }
 
 // DisplayLog writes the Logdash data to standand out, if testing in verbose mode
 // was turned on.
+//
+// Deprecated: a Capture writes its entries to the test log when the test fails.
 func DisplayLog() {
 	if !testing.Verbose() {
 		return
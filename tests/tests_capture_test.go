@@ -0,0 +1,64 @@
+package tests_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ardanlabs/kit/log"
+	"github.com/ardanlabs/kit/tests"
+)
+
+// TestCapture tests capturing the entries of parallel tests.
+func TestCapture(t *testing.T) {
+	t.Log("Given the need to assert on the entries logged by a test.")
+	{
+		for i := 0; i < 4; i++ {
+			traceID := fmt.Sprintf("trace%d", i)
+
+			t.Run(traceID, func(t *testing.T) {
+				t.Parallel()
+
+				c := tests.NewCapture(t, log.USER)
+
+				t.Log("\tWhen entries are logged by several goroutines.")
+				{
+					done := make(chan struct{})
+					for g := 0; g < 10; g++ {
+						go func() {
+							c.User(traceID, "worker", "Processed")
+							c.User("other", "worker", "Processed")
+							done <- struct{}{}
+						}()
+					}
+					for g := 0; g < 10; g++ {
+						<-done
+					}
+
+					c.Dev(traceID, "worker", "Not logged")
+					c.Error(traceID, "worker", errors.New("connection refused"), "Dialing")
+
+					if n := len(c.Entries(traceID)); n == 11 {
+						t.Logf("\t\t%v : Should capture the entries for the trace ID.", tests.Success)
+					} else {
+						t.Errorf("\t\t%v : Should capture the entries for the trace ID : got %d", tests.Failed, n)
+					}
+
+					c.AssertLogged(t, log.ERROR, "connection refused")
+					c.AssertNotLogged(t, log.DEV, "Not logged")
+				}
+
+				t.Log("\tWhen a fatal message is logged.")
+				{
+					c.Fatal(traceID, "worker", "Giving up")
+
+					if code, exited := c.Exited(); exited && code == 1 {
+						t.Logf("\t\t%v : Should record the exit.", tests.Success)
+					} else {
+						t.Errorf("\t\t%v : Should record the exit.", tests.Failed)
+					}
+				}
+			})
+		}
+	}
+}
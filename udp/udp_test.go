@@ -15,9 +15,6 @@ import (
 // TestUDP provide a test of listening for a connection and
 // echoing the data back.
 func TestUDP(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	t.Log("Given the need to listen and process UDP data.")
 	{
 		// Create a configuration.
@@ -87,9 +84,6 @@ func TestUDP(t *testing.T) {
 
 // Test udp.Addr works correctly.
 func TestUDPAddr(t *testing.T) {
-	tests.ResetLog()
-	defer tests.DisplayLog()
-
 	t.Log("Given the need to listen on any port and know that bound UDP address.")
 	{
 		// Create a configuration.
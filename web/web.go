@@ -13,6 +13,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/ardanlabs/kit/log"
 	"github.com/dimfeld/httptreemux"
 	"github.com/pborman/uuid"
 	"gopkg.in/go-playground/validator.v8"
@@ -124,6 +125,9 @@ func (a *App) Handle(verb, path string, handler Handler, mw ...Middleware) {
 		}
 		ctx := context.WithValue(r.Context(), KeyValues, &v)
 
+		// Make the trace id available to the log Ctx functions.
+		ctx = log.WithTraceID(ctx, v.TraceID)
+
 		// Set the trace id on the outgoing requests before any other header to
 		// ensure that the trace id is ALWAYS added to the request regardless of
 		// any error occuring or not.